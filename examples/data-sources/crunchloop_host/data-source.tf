@@ -1,3 +1,14 @@
+# Look up a host by name, failing the plan when it's offline
 data "crunchloop_host" "host" {
-  name = "crunchloop-host"
+  name           = "crunchloop-host"
+  require_online = true
+}
+
+# Or by id
+data "crunchloop_host" "by_id" {
+  id = "1"
+}
+
+output "host_status" {
+  value = data.crunchloop_host.by_id.status
 }
@@ -0,0 +1,9 @@
+# Every online host in the pve cluster
+data "crunchloop_hosts" "online" {
+  name_regex = "^pve-"
+  status     = "online"
+}
+
+output "online_host_ids" {
+  value = data.crunchloop_hosts.online.hosts[*].id
+}
@@ -0,0 +1,13 @@
+# Look up a vm by name, names must be unique
+data "crunchloop_vm" "database" {
+  name = "database-01"
+}
+
+# Or by id
+data "crunchloop_vm" "by_id" {
+  id = "42"
+}
+
+output "database_ip_address" {
+  value = data.crunchloop_vm.database.nic.ip_address
+}
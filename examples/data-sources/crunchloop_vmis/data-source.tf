@@ -0,0 +1,11 @@
+# Available ubuntu images, sorted by id
+data "crunchloop_vmis" "ubuntu" {
+  name_regex = "^ubuntu-"
+  status     = "available"
+}
+
+# Images are listed in the order they were registered, the last one is the
+# newest
+output "latest_ubuntu_vmi_id" {
+  value = element(data.crunchloop_vmis.ubuntu.vmis, length(data.crunchloop_vmis.ubuntu.vmis) - 1).id
+}
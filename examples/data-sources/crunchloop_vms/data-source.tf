@@ -0,0 +1,9 @@
+# Every running web server
+data "crunchloop_vms" "web" {
+  name_regex = "^web-\\d+$"
+  status     = "running"
+}
+
+output "web_ip_addresses" {
+  value = [for vm in data.crunchloop_vms.web.vms : vm.nic.ip_address]
+}
@@ -6,6 +6,34 @@ terraform {
   }
 }
 
+# Every attribute can also be provided through environment variables,
+# CRUNCHLOOP_URL and CRUNCHLOOP_API_TOKEN respectively.
+#
 provider "crunchloop" {
-  url = "http://localhost:3000"
+  url       = "http://localhost:3000"
+  api_token = var.crunchloop_api_token
+}
+
+variable "crunchloop_api_token" {
+  type      = string
+  sensitive = true
+}
+
+# In mock mode the provider uses an embedded in-memory backend, so modules can
+# be tested with `terraform test` without a Crunchloop instance. Seeded
+# objects get sequential ids, "1" and "2" for the hosts and "3" for the vmi.
+#
+provider "crunchloop" {
+  alias = "mock"
+  mode  = "mock"
+
+  mock = {
+    hosts = [
+      { name = "pve-01" },
+      { name = "pve-02", status = "offline" },
+    ]
+    vmis = [
+      { name = "Ubuntu 24.04 (noble)" },
+    ]
+  }
 }
@@ -0,0 +1,4 @@
+# Hosts can be imported by id. The API doesn't return the connection details,
+# they're adopted from the configuration on the next apply without replacing
+# the host.
+terraform import crunchloop_proxmox_host.beelink 1
@@ -0,0 +1,32 @@
+terraform {
+  required_providers {
+    crunchloop = {
+      source = "bilby91/crunchloop"
+    }
+  }
+}
+
+provider "crunchloop" {
+  url = "http://localhost:3000"
+}
+
+variable "proxmox_password" {
+  type      = string
+  sensitive = true
+}
+
+resource "crunchloop_proxmox_host" "beelink" {
+  name         = "beelink-01"
+  ip_address   = "192.168.1.10"
+  ssh_username = "root"
+  ssh_password = var.proxmox_password
+}
+
+resource "crunchloop_vm" "vm" {
+  name                       = "terraform-vm"
+  vmi_id                     = 1
+  host_id                    = crunchloop_proxmox_host.beelink.id
+  cores                      = 1
+  memory_megabytes           = 1024
+  root_volume_size_gigabytes = 10
+}
@@ -0,0 +1,34 @@
+terraform {
+  required_providers {
+    crunchloop = {
+      source = "bilby91/crunchloop"
+    }
+  }
+}
+
+provider "crunchloop" {
+  url = "http://localhost:3000"
+}
+
+# Changing the url or sha256 registers a new image and replaces the VMs
+# built from it.
+#
+resource "crunchloop_proxmox_vmi" "jammy" {
+  name   = "ubuntu-jammy-server-amd64-20241002"
+  url    = "https://cloud-images.ubuntu.com/jammy/20241002/jammy-server-cloudimg-amd64.img"
+  sha256 = "55c687a9a242fab7b0ec89ac69f9def77696c4e160e6f640879a0b0031a08318"
+
+  # The image is downloaded by the host within 30 minutes by default, allow
+  # for more on slow links.
+  timeouts {
+    create = "1h"
+  }
+}
+
+resource "crunchloop_vm" "vm" {
+  name                       = "terraform-vm"
+  vmi_id                     = crunchloop_proxmox_vmi.jammy.id
+  cores                      = 1
+  memory_megabytes           = 1024
+  root_volume_size_gigabytes = 10
+}
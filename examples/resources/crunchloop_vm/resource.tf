@@ -68,4 +68,41 @@ resource "crunchloop_vm" "with_cloudinit" {
   memory_megabytes           = 1024
   root_volume_size_gigabytes = 10
   user_data                  = data.cloudinit_config.cloudinit.rendered
-}
\ No newline at end of file
+}
+
+# Large root volumes or busy hosts may need more time than the provider
+# default_timeout to come up
+#
+resource "crunchloop_vm" "with_timeouts" {
+  name                       = "terraform-with-timeouts"
+  vmi_id                     = data.crunchloop_vmi.ubuntu.id
+  cores                      = 2
+  memory_megabytes           = 1024
+  root_volume_size_gigabytes = 50
+
+  timeouts {
+    create = "20m"
+    update = "10m"
+    delete = "10m"
+  }
+}
+
+# Wait for the VM to lease an IP address so it can be used right away, e.g.
+# in a DNS record or a provisioner connection
+#
+resource "crunchloop_vm" "with_ip_address" {
+  name                       = "terraform-with-ip-address"
+  vmi_id                     = data.crunchloop_vmi.ubuntu.id
+  cores                      = 1
+  memory_megabytes           = 1024
+  root_volume_size_gigabytes = 10
+  wait_for_ip_address        = true
+}
+
+output "ip_address" {
+  value = crunchloop_vm.with_ip_address.ip_address
+}
+
+output "root_volume_size_bytes" {
+  value = crunchloop_vm.with_ip_address.root_volume.size_bytes
+}
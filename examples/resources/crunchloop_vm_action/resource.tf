@@ -0,0 +1,26 @@
+terraform {
+  required_providers {
+    crunchloop = {
+      source = "bilby91/crunchloop"
+    }
+  }
+}
+
+provider "crunchloop" {
+  url = "http://localhost:3000"
+}
+
+variable "vm_id" {
+  type = string
+}
+
+# Bounce the vm once, bump `rollout` to reboot it again
+#
+resource "crunchloop_vm_action" "reboot" {
+  vm_id  = var.vm_id
+  action = "reboot"
+
+  triggers = {
+    rollout = "2024-10-02"
+  }
+}
@@ -25,9 +25,16 @@ resource "crunchloop_vm" "vm" {
   cores                      = 1
   memory_megabytes           = 1024
   root_volume_size_gigabytes = 10
+  user_data                  = base64encode(file("${path.module}/cloud-init.sh"))
 }
 
 resource "crunchloop_vm_state" "state" {
   vm_id  = crunchloop_vm.vm.id
   status = "running"
+
+  # Reboot the vm whenever its user data changes so the new configuration
+  # is applied without replacing it
+  reboot_triggers = {
+    user_data = sha256(crunchloop_vm.vm.user_data)
+  }
 }
\ No newline at end of file
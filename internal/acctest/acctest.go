@@ -0,0 +1,55 @@
+// Package acctest wires the provider to an in-memory Crunchloop API so
+// acceptance tests can run with resource.Test without a live deployment:
+//
+//	server, url := acctest.NewServer(t)
+//	host := server.AddHost("host-1", client.Online)
+//
+//	resource.Test(t, resource.TestCase{
+//		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+//		Steps: []resource.TestStep{
+//			{
+//				Config: acctest.ProviderConfig(url) + `data "crunchloop_host" "test" { name = "host-1" }`,
+//			},
+//		},
+//	})
+package acctest
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/mockapi"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/provider"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// ProtoV6ProviderFactories are used to instantiate the provider during
+// acceptance testing. The factory function is invoked for every Terraform CLI
+// command executed to create a provider server to which the CLI can
+// reattach.
+var ProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+	"crunchloop": providerserver.NewProtocol6WithError(provider.New("test")()),
+}
+
+// NewServer starts an in-memory Crunchloop API for the duration of the test
+// and returns it along with its url.
+func NewServer(t testing.TB) (*mockapi.Server, string) {
+	t.Helper()
+
+	server := mockapi.New(mockapi.Options{})
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	return server, httpServer.URL
+}
+
+// ProviderConfig returns the provider block pointing to the given url.
+func ProviderConfig(url string) string {
+	return fmt.Sprintf(`
+provider "crunchloop" {
+  url = %q
+}
+`, url)
+}
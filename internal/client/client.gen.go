@@ -16,6 +16,10 @@ import (
 	"github.com/oapi-codegen/runtime"
 )
 
+const (
+	BearerAuthScopes = "bearerAuth.Scopes"
+)
+
 // Defines values for ErrorCode.
 const (
 	InputError     ErrorCode = "input_error"
@@ -38,23 +42,38 @@ const (
 	VirtualMachineStatusUpdating  VirtualMachineStatus = "updating"
 )
 
+// Defines values for VirtualMachineImageStatus.
+const (
+	VirtualMachineImageStatusAvailable VirtualMachineImageStatus = "available"
+	VirtualMachineImageStatusCreating  VirtualMachineImageStatus = "creating"
+	VirtualMachineImageStatusDeleting  VirtualMachineImageStatus = "deleting"
+	VirtualMachineImageStatusFailed    VirtualMachineImageStatus = "failed"
+)
+
 // Defines values for VolumeStatus.
 const (
-	VolumeStatusAvailable VolumeStatus = "available"
-	VolumeStatusCreating  VolumeStatus = "creating"
-	VolumeStatusDeleting  VolumeStatus = "deleting"
-	VolumeStatusInUse     VolumeStatus = "in_use"
+	Available VolumeStatus = "available"
+	Creating  VolumeStatus = "creating"
+	Deleting  VolumeStatus = "deleting"
+	InUse     VolumeStatus = "in_use"
 )
 
 // Error defines model for Error.
 type Error struct {
-	Code    ErrorCode `json:"code"`
-	Message string    `json:"message"`
+	Code    ErrorCode      `json:"code"`
+	Details *[]ErrorDetail `json:"details,omitempty"`
+	Message string         `json:"message"`
 }
 
 // ErrorCode defines model for Error.Code.
 type ErrorCode string
 
+// ErrorDetail defines model for ErrorDetail.
+type ErrorDetail struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
 // Host defines model for Host.
 type Host struct {
 	Id     *int32      `json:"id,omitempty"`
@@ -98,13 +117,26 @@ type VirtualMachine struct {
 // VirtualMachineStatus defines model for VirtualMachine.Status.
 type VirtualMachineStatus string
 
+// VirtualMachineCollection defines model for VirtualMachineCollection.
+type VirtualMachineCollection struct {
+	Data    *[]VirtualMachine `json:"data,omitempty"`
+	HasMore *bool             `json:"has_more,omitempty"`
+	Object  *string           `json:"object,omitempty"`
+}
+
 // VirtualMachineImage defines model for VirtualMachineImage.
 type VirtualMachineImage struct {
-	Id     *int32  `json:"id,omitempty"`
-	Name   *string `json:"name,omitempty"`
-	Object *string `json:"object,omitempty"`
+	Id     *int32                     `json:"id,omitempty"`
+	Name   *string                    `json:"name,omitempty"`
+	Object *string                    `json:"object,omitempty"`
+	Sha256 *string                    `json:"sha256,omitempty"`
+	Status *VirtualMachineImageStatus `json:"status,omitempty"`
+	Url    *string                    `json:"url,omitempty"`
 }
 
+// VirtualMachineImageStatus defines model for VirtualMachineImage.Status.
+type VirtualMachineImageStatus string
+
 // VirtualMachineImageCollection defines model for VirtualMachineImageCollection.
 type VirtualMachineImageCollection struct {
 	Data    *[]VirtualMachineImage `json:"data,omitempty"`
@@ -124,12 +156,36 @@ type Volume struct {
 // VolumeStatus defines model for Volume.Status.
 type VolumeStatus string
 
+// Limit defines model for Limit.
+type Limit = int32
+
+// StartingAfter defines model for StartingAfter.
+type StartingAfter = int32
+
+// ListHostsParams defines parameters for ListHosts.
+type ListHostsParams struct {
+	// Limit Maximum number of objects to return
+	Limit *Limit `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// StartingAfter Cursor for pagination, id of the last object of the previous page
+	StartingAfter *StartingAfter `form:"starting_after,omitempty" json:"starting_after,omitempty"`
+}
+
 // CreateProxmoxHostJSONBody defines parameters for CreateProxmoxHost.
 type CreateProxmoxHostJSONBody struct {
-	IpAddress   string  `json:"ip_address"`
-	Name        string  `json:"name"`
-	SshPassword *string `json:"ssh_password,omitempty"`
-	SshUsername *string `json:"ssh_username,omitempty"`
+	IpAddress   string `json:"ip_address"`
+	Name        string `json:"name"`
+	SshPassword string `json:"ssh_password"`
+	SshUsername string `json:"ssh_username"`
+}
+
+// ListVmisParams defines parameters for ListVmis.
+type ListVmisParams struct {
+	// Limit Maximum number of objects to return
+	Limit *Limit `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// StartingAfter Cursor for pagination, id of the last object of the previous page
+	StartingAfter *StartingAfter `form:"starting_after,omitempty" json:"starting_after,omitempty"`
 }
 
 // CreateProxmoxVmiJSONBody defines parameters for CreateProxmoxVmi.
@@ -139,6 +195,15 @@ type CreateProxmoxVmiJSONBody struct {
 	Url    string `json:"url"`
 }
 
+// ListVmsParams defines parameters for ListVms.
+type ListVmsParams struct {
+	// Limit Maximum number of objects to return
+	Limit *Limit `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// StartingAfter Cursor for pagination, id of the last object of the previous page
+	StartingAfter *StartingAfter `form:"starting_after,omitempty" json:"starting_after,omitempty"`
+}
+
 // CreateVmJSONBody defines parameters for CreateVm.
 type CreateVmJSONBody struct {
 	Cores                   int32   `json:"cores"`
@@ -244,7 +309,7 @@ func WithRequestEditorFn(fn RequestEditorFn) ClientOption {
 // The interface specification for the client above.
 type ClientInterface interface {
 	// ListHosts request
-	ListHosts(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+	ListHosts(ctx context.Context, params *ListHostsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
 
 	// CreateProxmoxHostWithBody request with any body
 	CreateProxmoxHostWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
@@ -252,13 +317,16 @@ type ClientInterface interface {
 	CreateProxmoxHost(ctx context.Context, body CreateProxmoxHostJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
 
 	// ListVmis request
-	ListVmis(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+	ListVmis(ctx context.Context, params *ListVmisParams, reqEditors ...RequestEditorFn) (*http.Response, error)
 
 	// CreateProxmoxVmiWithBody request with any body
 	CreateProxmoxVmiWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
 
 	CreateProxmoxVmi(ctx context.Context, body CreateProxmoxVmiJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
 
+	// ListVms request
+	ListVms(ctx context.Context, params *ListVmsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// CreateVmWithBody request with any body
 	CreateVmWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
 
@@ -285,8 +353,8 @@ type ClientInterface interface {
 	StopVm(ctx context.Context, id int32, reqEditors ...RequestEditorFn) (*http.Response, error)
 }
 
-func (c *Client) ListHosts(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewListHostsRequest(c.Server)
+func (c *Client) ListHosts(ctx context.Context, params *ListHostsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListHostsRequest(c.Server, params)
 	if err != nil {
 		return nil, err
 	}
@@ -321,8 +389,8 @@ func (c *Client) CreateProxmoxHost(ctx context.Context, body CreateProxmoxHostJS
 	return c.Client.Do(req)
 }
 
-func (c *Client) ListVmis(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewListVmisRequest(c.Server)
+func (c *Client) ListVmis(ctx context.Context, params *ListVmisParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListVmisRequest(c.Server, params)
 	if err != nil {
 		return nil, err
 	}
@@ -357,6 +425,18 @@ func (c *Client) CreateProxmoxVmi(ctx context.Context, body CreateProxmoxVmiJSON
 	return c.Client.Do(req)
 }
 
+func (c *Client) ListVms(ctx context.Context, params *ListVmsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListVmsRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
 func (c *Client) CreateVmWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
 	req, err := NewCreateVmRequestWithBody(c.Server, contentType, body)
 	if err != nil {
@@ -466,7 +546,7 @@ func (c *Client) StopVm(ctx context.Context, id int32, reqEditors ...RequestEdit
 }
 
 // NewListHostsRequest generates requests for ListHosts
-func NewListHostsRequest(server string) (*http.Request, error) {
+func NewListHostsRequest(server string, params *ListHostsParams) (*http.Request, error) {
 	var err error
 
 	serverURL, err := url.Parse(server)
@@ -484,6 +564,44 @@ func NewListHostsRequest(server string) (*http.Request, error) {
 		return nil, err
 	}
 
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Limit != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "limit", runtime.ParamLocationQuery, *params.Limit); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.StartingAfter != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "starting_after", runtime.ParamLocationQuery, *params.StartingAfter); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
 	req, err := http.NewRequest("GET", queryURL.String(), nil)
 	if err != nil {
 		return nil, err
@@ -533,7 +651,7 @@ func NewCreateProxmoxHostRequestWithBody(server string, contentType string, body
 }
 
 // NewListVmisRequest generates requests for ListVmis
-func NewListVmisRequest(server string) (*http.Request, error) {
+func NewListVmisRequest(server string, params *ListVmisParams) (*http.Request, error) {
 	var err error
 
 	serverURL, err := url.Parse(server)
@@ -551,6 +669,44 @@ func NewListVmisRequest(server string) (*http.Request, error) {
 		return nil, err
 	}
 
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Limit != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "limit", runtime.ParamLocationQuery, *params.Limit); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.StartingAfter != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "starting_after", runtime.ParamLocationQuery, *params.StartingAfter); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
 	req, err := http.NewRequest("GET", queryURL.String(), nil)
 	if err != nil {
 		return nil, err
@@ -599,6 +755,71 @@ func NewCreateProxmoxVmiRequestWithBody(server string, contentType string, body
 	return req, nil
 }
 
+// NewListVmsRequest generates requests for ListVms
+func NewListVmsRequest(server string, params *ListVmsParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v1/vms")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Limit != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "limit", runtime.ParamLocationQuery, *params.Limit); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.StartingAfter != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "starting_after", runtime.ParamLocationQuery, *params.StartingAfter); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
 // NewCreateVmRequest calls the generic CreateVm builder with application/json body
 func NewCreateVmRequest(server string, body CreateVmJSONRequestBody) (*http.Request, error) {
 	var bodyReader io.Reader
@@ -900,7 +1121,7 @@ func WithBaseURL(baseURL string) ClientOption {
 // ClientWithResponsesInterface is the interface specification for the client with responses above.
 type ClientWithResponsesInterface interface {
 	// ListHostsWithResponse request
-	ListHostsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListHostsResponse, error)
+	ListHostsWithResponse(ctx context.Context, params *ListHostsParams, reqEditors ...RequestEditorFn) (*ListHostsResponse, error)
 
 	// CreateProxmoxHostWithBodyWithResponse request with any body
 	CreateProxmoxHostWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CreateProxmoxHostResponse, error)
@@ -908,13 +1129,16 @@ type ClientWithResponsesInterface interface {
 	CreateProxmoxHostWithResponse(ctx context.Context, body CreateProxmoxHostJSONRequestBody, reqEditors ...RequestEditorFn) (*CreateProxmoxHostResponse, error)
 
 	// ListVmisWithResponse request
-	ListVmisWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListVmisResponse, error)
+	ListVmisWithResponse(ctx context.Context, params *ListVmisParams, reqEditors ...RequestEditorFn) (*ListVmisResponse, error)
 
 	// CreateProxmoxVmiWithBodyWithResponse request with any body
 	CreateProxmoxVmiWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CreateProxmoxVmiResponse, error)
 
 	CreateProxmoxVmiWithResponse(ctx context.Context, body CreateProxmoxVmiJSONRequestBody, reqEditors ...RequestEditorFn) (*CreateProxmoxVmiResponse, error)
 
+	// ListVmsWithResponse request
+	ListVmsWithResponse(ctx context.Context, params *ListVmsParams, reqEditors ...RequestEditorFn) (*ListVmsResponse, error)
+
 	// CreateVmWithBodyWithResponse request with any body
 	CreateVmWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CreateVmResponse, error)
 
@@ -1031,6 +1255,28 @@ func (r CreateProxmoxVmiResponse) StatusCode() int {
 	return 0
 }
 
+type ListVmsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *VirtualMachineCollection
+}
+
+// Status returns HTTPResponse.Status
+func (r ListVmsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ListVmsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
 type CreateVmResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
@@ -1197,8 +1443,8 @@ func (r StopVmResponse) StatusCode() int {
 }
 
 // ListHostsWithResponse request returning *ListHostsResponse
-func (c *ClientWithResponses) ListHostsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListHostsResponse, error) {
-	rsp, err := c.ListHosts(ctx, reqEditors...)
+func (c *ClientWithResponses) ListHostsWithResponse(ctx context.Context, params *ListHostsParams, reqEditors ...RequestEditorFn) (*ListHostsResponse, error) {
+	rsp, err := c.ListHosts(ctx, params, reqEditors...)
 	if err != nil {
 		return nil, err
 	}
@@ -1223,8 +1469,8 @@ func (c *ClientWithResponses) CreateProxmoxHostWithResponse(ctx context.Context,
 }
 
 // ListVmisWithResponse request returning *ListVmisResponse
-func (c *ClientWithResponses) ListVmisWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListVmisResponse, error) {
-	rsp, err := c.ListVmis(ctx, reqEditors...)
+func (c *ClientWithResponses) ListVmisWithResponse(ctx context.Context, params *ListVmisParams, reqEditors ...RequestEditorFn) (*ListVmisResponse, error) {
+	rsp, err := c.ListVmis(ctx, params, reqEditors...)
 	if err != nil {
 		return nil, err
 	}
@@ -1248,6 +1494,15 @@ func (c *ClientWithResponses) CreateProxmoxVmiWithResponse(ctx context.Context,
 	return ParseCreateProxmoxVmiResponse(rsp)
 }
 
+// ListVmsWithResponse request returning *ListVmsResponse
+func (c *ClientWithResponses) ListVmsWithResponse(ctx context.Context, params *ListVmsParams, reqEditors ...RequestEditorFn) (*ListVmsResponse, error) {
+	rsp, err := c.ListVms(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseListVmsResponse(rsp)
+}
+
 // CreateVmWithBodyWithResponse request with arbitrary body returning *CreateVmResponse
 func (c *ClientWithResponses) CreateVmWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CreateVmResponse, error) {
 	rsp, err := c.CreateVmWithBody(ctx, contentType, body, reqEditors...)
@@ -1445,6 +1700,32 @@ func ParseCreateProxmoxVmiResponse(rsp *http.Response) (*CreateProxmoxVmiRespons
 	return response, nil
 }
 
+// ParseListVmsResponse parses an HTTP response from a ListVmsWithResponse call
+func ParseListVmsResponse(rsp *http.Response) (*ListVmsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListVmsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest VirtualMachineCollection
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
 // ParseCreateVmResponse parses an HTTP response from a CreateVmWithResponse call
 func ParseCreateVmResponse(rsp *http.Response) (*CreateVmResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
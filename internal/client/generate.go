@@ -1,3 +1,4 @@
 package client
 
 //go:generate go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen -config config.yaml openapi.yaml
+//go:generate go run ../../tools/genlimits -spec openapi.yaml -output limits.gen.go
@@ -0,0 +1,21 @@
+// Code generated by genlimits from openapi.yaml. DO NOT EDIT.
+
+package client
+
+// Limits of the createVm request body.
+const (
+	CreateVmCoresMinimum                   int32 = 1
+	CreateVmCoresMaximum                   int32 = 2
+	CreateVmMemoryMegabytesMinimum         int32 = 128
+	CreateVmMemoryMegabytesMaximum         int32 = 1024
+	CreateVmRootVolumeSizeGigabytesMinimum int32 = 5
+	CreateVmRootVolumeSizeGigabytesMaximum int32 = 50
+)
+
+// Limits of the updateVm request body.
+const (
+	UpdateVmCoresMinimum           int32 = 1
+	UpdateVmCoresMaximum           int32 = 2
+	UpdateVmMemoryMegabytesMinimum int32 = 128
+	UpdateVmMemoryMegabytesMaximum int32 = 1024
+)
@@ -0,0 +1,118 @@
+package mockapi
+
+import (
+	"net/http"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
+)
+
+// AddHost seeds a host with the given status and returns it. When a host with
+// the same name exists, its status is updated instead, so seeds can be
+// applied again to a restored state.
+func (s *Server) AddHost(name string, status client.HostStatus) client.Host {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, host := range s.hosts {
+		if *host.Name == name {
+			host.Status = &status
+			return *host
+		}
+	}
+
+	host := s.createHost(name, status)
+	s.seeded[*host.Id] = true
+
+	return *host
+}
+
+// SetHostStatus changes the status of a host, e.g. to simulate a node going
+// offline. It returns false when the host doesn't exist.
+func (s *Server) SetHostStatus(id int32, status client.HostStatus) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	host, ok := s.hosts[id]
+	if !ok {
+		return false
+	}
+
+	host.Status = &status
+
+	return true
+}
+
+func (s *Server) createHost(name string, status client.HostStatus) *client.Host {
+	host := &client.Host{
+		Id:     pointer(s.newId()),
+		Object: pointer("host"),
+		Name:   pointer(name),
+		Status: &status,
+	}
+	s.hosts[*host.Id] = host
+
+	return host
+}
+
+func (s *Server) routeHosts(w http.ResponseWriter, r *http.Request, segments []string) {
+	switch {
+	case len(segments) == 0:
+		if r.Method != http.MethodGet {
+			writeMethodNotAllowed(w)
+			return
+		}
+
+		s.listHosts(w, r)
+	case len(segments) == 1 && segments[0] == "proxmox":
+		if r.Method != http.MethodPost {
+			writeMethodNotAllowed(w)
+			return
+		}
+
+		s.createProxmoxHost(w, r)
+	default:
+		writeNotFound(w, "route")
+	}
+}
+
+func (s *Server) listHosts(w http.ResponseWriter, r *http.Request) {
+	ids := make([]int32, 0, len(s.hosts))
+	for id := range s.hosts {
+		ids = append(ids, id)
+	}
+
+	ids, hasMore, ok := page(w, r, ids)
+	if !ok {
+		return
+	}
+
+	data := make([]client.Host, 0, len(ids))
+	for _, id := range ids {
+		data = append(data, *s.hosts[id])
+	}
+
+	writeJSON(w, http.StatusOK, client.HostCollection{
+		Object:  pointer("list"),
+		HasMore: &hasMore,
+		Data:    &data,
+	})
+}
+
+func (s *Server) createProxmoxHost(w http.ResponseWriter, r *http.Request) {
+	var body client.CreateProxmoxHostJSONRequestBody
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	var details []client.ErrorDetail
+	details = validateRequired(details, "name", body.Name)
+	details = validateRequired(details, "ip_address", body.IpAddress)
+	details = validateRequired(details, "ssh_username", body.SshUsername)
+	details = validateRequired(details, "ssh_password", body.SshPassword)
+	if len(details) > 0 {
+		writeInputError(w, details...)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, s.createHost(body.Name, client.Online))
+}
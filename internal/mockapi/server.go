@@ -0,0 +1,351 @@
+// Package mockapi implements the Crunchloop API described by
+// internal/client/openapi.yaml in memory. It backs the provider `mock` mode,
+// so modules can be tested without a live Crunchloop deployment, and the
+// provider acceptance tests.
+//
+// Objects go through asynchronous transitions between the statuses of the
+// spec, e.g. a vm is `creating` right after it's created and becomes `running`
+// after it has been observed a few times, so the provider waiters are
+// exercised too.
+package mockapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
+)
+
+// defaultTransitionPolls is the number of reads an object has to be observed
+// in an intermediate status before it reaches its target status.
+const defaultTransitionPolls = 1
+
+// serverError is the code of errors caused by the mock itself. The spec only
+// defines codes for client errors, a dedicated one keeps them from being
+// reported as invalid input.
+const serverError client.ErrorCode = "server_error"
+
+// defaultPageSize and maxPageSize mirror the Limit parameter of the spec.
+const (
+	defaultPageSize = 25
+	maxPageSize     = 100
+)
+
+// Options configures a Server.
+type Options struct {
+	// TransitionPolls is the number of reads an object stays in an
+	// intermediate status (e.g. `creating`) before reaching its target
+	// status. Defaults to 1.
+	TransitionPolls int
+
+	// Hosts and Vmis are seeded when the server is created, in order, so
+	// they get the first identifiers.
+	Hosts []HostSeed
+	Vmis  []string
+}
+
+// HostSeed describes a host seeded through Options.
+type HostSeed struct {
+	Name   string            `json:"name"`
+	Status client.HostStatus `json:"status"`
+}
+
+// Server is an in-memory implementation of the Crunchloop API. It's safe for
+// concurrent use.
+type Server struct {
+	mu              sync.Mutex
+	transitionPolls int
+	nextId          int32
+	nextRequestId   int
+	requests        []string
+	statePath       string
+	seeds           seeds
+	seeded          map[int32]bool
+	hosts           map[int32]*client.Host
+	vmis            map[int32]*vmiRecord
+	vms             map[int32]*vmRecord
+}
+
+// transition describes a pending status change of an object.
+type transition struct {
+	status string
+	polls  int
+	remove bool
+}
+
+// New returns an empty Server.
+func New(options Options) *Server {
+	polls := options.TransitionPolls
+	if polls <= 0 {
+		polls = defaultTransitionPolls
+	}
+
+	server := &Server{
+		transitionPolls: polls,
+		seeds:           seeds{Hosts: options.Hosts, Vmis: options.Vmis},
+		seeded:          map[int32]bool{},
+		hosts:           map[int32]*client.Host{},
+		vmis:            map[int32]*vmiRecord{},
+		vms:             map[int32]*vmRecord{},
+	}
+
+	for _, host := range options.Hosts {
+		server.AddHost(host.Name, host.Status)
+	}
+
+	for _, vmi := range options.Vmis {
+		server.AddVmi(vmi)
+	}
+
+	return server
+}
+
+// ServeHTTP routes the request to the handler of its path.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextRequestId++
+	w.Header().Set("X-Request-Id", fmt.Sprintf("mockapi-%d", s.nextRequestId))
+	s.requests = append(s.requests, fmt.Sprintf("%s %s", r.Method, r.URL.Path))
+
+	if s.statePath == "" {
+		s.route(w, r)
+		return
+	}
+
+	// Persist the state before answering, so a client never observes a
+	// change that could be lost.
+	recorder := newResponse()
+	s.route(recorder, r)
+
+	if err := s.save(); err != nil {
+		writeError(w, http.StatusInternalServerError, client.Error{
+			Code:    serverError,
+			Message: fmt.Sprintf("failed to save state: %s", err),
+		})
+		return
+	}
+
+	for key, values := range recorder.Header() {
+		w.Header()[key] = values
+	}
+	w.WriteHeader(recorder.status)
+	_, _ = w.Write(recorder.body.Bytes())
+}
+
+// Requests returns the method and path of every request served so far, e.g.
+// `PUT /api/v1/vms/3`. It's meant for assertions.
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.requests...)
+}
+
+// Do serves the request in-process, so the server can be used as the HTTP
+// client of the generated client.
+func (s *Server) Do(r *http.Request) (*http.Response, error) {
+	recorder := newResponse()
+	s.ServeHTTP(recorder, r)
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorder.status, http.StatusText(recorder.status)),
+		StatusCode:    recorder.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        recorder.header,
+		Body:          io.NopCloser(bytes.NewReader(recorder.body.Bytes())),
+		ContentLength: int64(recorder.body.Len()),
+		Request:       r,
+	}, nil
+}
+
+// response is an http.ResponseWriter buffering the response in memory.
+type response struct {
+	header      http.Header
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func newResponse() *response {
+	return &response{header: http.Header{}, status: http.StatusOK}
+}
+
+func (r *response) Header() http.Header {
+	return r.header
+}
+
+func (r *response) WriteHeader(status int) {
+	if r.wroteHeader {
+		return
+	}
+
+	r.status = status
+	r.wroteHeader = true
+}
+
+func (r *response) Write(content []byte) (int, error) {
+	r.wroteHeader = true
+	return r.body.Write(content)
+}
+
+func (s *Server) route(w http.ResponseWriter, r *http.Request) {
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1"), "/"), "/")
+
+	switch segments[0] {
+	case "vms":
+		s.routeVms(w, r, segments[1:])
+	case "hosts":
+		s.routeHosts(w, r, segments[1:])
+	case "vmis":
+		s.routeVmis(w, r, segments[1:])
+	default:
+		writeNotFound(w, "route")
+	}
+}
+
+func (s *Server) newId() int32 {
+	s.nextId++
+	return s.nextId
+}
+
+func (s *Server) newTransition(status string) *transition {
+	return &transition{status: status, polls: s.transitionPolls}
+}
+
+// advance counts an observation of an object with a pending transition and
+// reports whether the transition is due.
+func (t *transition) advance() bool {
+	t.polls--
+	return t.polls <= 0
+}
+
+// parseId parses the {id} path parameter, writing a not found error when it
+// isn't a valid identifier.
+func parseId(w http.ResponseWriter, value string, object string) (int32, bool) {
+	id, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		writeNotFound(w, object)
+		return 0, false
+	}
+
+	return int32(id), true
+}
+
+// page applies the limit and starting_after query parameters to the sorted
+// identifiers of a collection.
+func page(w http.ResponseWriter, r *http.Request, ids []int32) ([]int32, bool, bool) {
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	limit := defaultPageSize
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxPageSize {
+			writeInputError(w, client.ErrorDetail{Field: "limit", Message: fmt.Sprintf("must be between 1 and %d", maxPageSize)})
+			return nil, false, false
+		}
+
+		limit = parsed
+	}
+
+	if value := r.URL.Query().Get("starting_after"); value != "" {
+		startingAfter, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			writeInputError(w, client.ErrorDetail{Field: "starting_after", Message: "must be an identifier"})
+			return nil, false, false
+		}
+
+		index := sort.Search(len(ids), func(i int) bool { return ids[i] > int32(startingAfter) })
+		ids = ids[index:]
+	}
+
+	if len(ids) > limit {
+		return ids[:limit], true, true
+	}
+
+	return ids, false, true
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, body interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+		writeError(w, http.StatusBadRequest, client.Error{
+			Code:    client.InputError,
+			Message: fmt.Sprintf("invalid request body: %s", err),
+		})
+		return false
+	}
+
+	return true
+}
+
+// validateRange returns an error detail when value is outside [min, max].
+func validateRange(details []client.ErrorDetail, field string, value, min, max int32) []client.ErrorDetail {
+	if value < min {
+		return append(details, client.ErrorDetail{Field: field, Message: fmt.Sprintf("must be greater than or equal to %d", min)})
+	}
+
+	if value > max {
+		return append(details, client.ErrorDetail{Field: field, Message: fmt.Sprintf("must be less than or equal to %d", max)})
+	}
+
+	return details
+}
+
+func validateRequired(details []client.ErrorDetail, field string, value string) []client.ErrorDetail {
+	if value == "" {
+		return append(details, client.ErrorDetail{Field: field, Message: "can't be blank"})
+	}
+
+	return details
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, body client.Error) {
+	writeJSON(w, status, body)
+}
+
+func writeNotFound(w http.ResponseWriter, object string) {
+	writeError(w, http.StatusNotFound, client.Error{
+		Code:    client.RecordNotFound,
+		Message: fmt.Sprintf("%s not found", object),
+	})
+}
+
+func writeInputError(w http.ResponseWriter, details ...client.ErrorDetail) {
+	messages := make([]string, 0, len(details))
+	for _, detail := range details {
+		messages = append(messages, fmt.Sprintf("%s %s", detail.Field, detail.Message))
+	}
+
+	writeError(w, http.StatusBadRequest, client.Error{
+		Code:    client.InputError,
+		Message: strings.Join(messages, ", "),
+		Details: &details,
+	})
+}
+
+func writeMethodNotAllowed(w http.ResponseWriter) {
+	writeError(w, http.StatusMethodNotAllowed, client.Error{
+		Code:    client.InputError,
+		Message: "method not allowed",
+	})
+}
+
+func pointer[T any](value T) *T {
+	return &value
+}
@@ -0,0 +1,228 @@
+package mockapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
+)
+
+// Open returns a Server whose state is stored in the file at path. The state
+// is loaded when the file exists and saved after every request, so it
+// survives across processes, e.g. Terraform starting a new provider process
+// for every command.
+//
+// The file is removed once only seeded objects remain, so identifiers start
+// over on the next run. A state saved with different seeds is discarded for
+// the same reason.
+func Open(path string, options Options) (*Server, error) {
+	server := New(options)
+	server.statePath = path
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return server, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state: %w", err)
+	}
+
+	var state serverState
+	if err := json.Unmarshal(content, &state); err != nil {
+		return nil, fmt.Errorf("failed to decode state %s: %w", path, err)
+	}
+
+	if !state.Seeds.equal(server.seeds) {
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("failed to discard state: %w", err)
+		}
+
+		return server, nil
+	}
+
+	server.restore(state)
+
+	return server, nil
+}
+
+// serverState is the serialized form of the server state.
+type serverState struct {
+	Seeds  seeds         `json:"seeds"`
+	NextId int32         `json:"next_id"`
+	Seeded []int32       `json:"seeded"`
+	Hosts  []client.Host `json:"hosts"`
+	Vmis   []vmiState    `json:"vmis"`
+	Vms    []vmState     `json:"vms"`
+}
+
+// seeds are the objects seeded through Options.
+type seeds struct {
+	Hosts []HostSeed `json:"hosts"`
+	Vmis  []string   `json:"vmis"`
+}
+
+func (s seeds) equal(other seeds) bool {
+	if len(s.Hosts) != len(other.Hosts) || len(s.Vmis) != len(other.Vmis) {
+		return false
+	}
+
+	for i := range s.Hosts {
+		if s.Hosts[i] != other.Hosts[i] {
+			return false
+		}
+	}
+
+	for i := range s.Vmis {
+		if s.Vmis[i] != other.Vmis[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+type vmiState struct {
+	Vmi  client.VirtualMachineImage `json:"vmi"`
+	Next *transitionState           `json:"next,omitempty"`
+}
+
+type vmState struct {
+	Vm     client.VirtualMachine `json:"vm"`
+	Next   *transitionState      `json:"next,omitempty"`
+	Leases int                   `json:"leases,omitempty"`
+}
+
+type transitionState struct {
+	Status string `json:"status"`
+	Polls  int    `json:"polls"`
+	Remove bool   `json:"remove"`
+}
+
+func (s *Server) save() error {
+	if len(s.vms) == 0 && len(s.seeded) == len(s.hosts)+len(s.vmis) {
+		if err := os.Remove(s.statePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+
+		return nil
+	}
+
+	content, err := json.MarshalIndent(s.snapshot(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first, a partially written state would be
+	// worse than a lost request.
+	temporary, err := os.CreateTemp(filepath.Dir(s.statePath), filepath.Base(s.statePath)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temporary.Name())
+
+	if _, err := temporary.Write(content); err != nil {
+		temporary.Close()
+		return err
+	}
+
+	if err := temporary.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(temporary.Name(), s.statePath)
+}
+
+func (s *Server) snapshot() serverState {
+	state := serverState{Seeds: s.seeds, NextId: s.nextId}
+
+	for id := range s.seeded {
+		state.Seeded = append(state.Seeded, id)
+	}
+	sort.Slice(state.Seeded, func(i, j int) bool { return state.Seeded[i] < state.Seeded[j] })
+
+	for _, id := range sortedIds(s.hosts) {
+		state.Hosts = append(state.Hosts, *s.hosts[id])
+	}
+
+	for _, id := range sortedIds(s.vmis) {
+		record := s.vmis[id]
+		state.Vmis = append(state.Vmis, vmiState{Vmi: record.vmi, Next: record.next.state()})
+	}
+
+	for _, id := range sortedIds(s.vms) {
+		record := s.vms[id]
+		state.Vms = append(state.Vms, vmState{Vm: record.vm, Next: record.next.state(), Leases: record.leases})
+	}
+
+	return state
+}
+
+// restore replaces the state of the server, including the objects seeded
+// when it was created.
+func (s *Server) restore(state serverState) {
+	s.nextId = state.NextId
+	s.seeded = map[int32]bool{}
+	s.hosts = map[int32]*client.Host{}
+	s.vmis = map[int32]*vmiRecord{}
+	s.vms = map[int32]*vmRecord{}
+
+	for _, id := range state.Seeded {
+		s.seeded[id] = true
+	}
+
+	for i := range state.Hosts {
+		host := state.Hosts[i]
+		s.hosts[*host.Id] = &host
+	}
+
+	for _, vmi := range state.Vmis {
+		s.vmis[*vmi.Vmi.Id] = &vmiRecord{vmi: vmi.Vmi, next: vmi.Next.transition()}
+	}
+
+	for _, vm := range state.Vms {
+		record := &vmRecord{vm: vm.Vm, next: vm.Next.transition(), leases: vm.Leases}
+
+		// Share the host and vmi with the vm again, so changes to them are
+		// reflected on the vm like before the state was saved.
+		if host, ok := s.hosts[*record.vm.Host.Id]; ok {
+			record.vm.Host = host
+		}
+
+		if vmi, ok := s.vmis[*record.vm.Vmi.Id]; ok {
+			record.vm.Vmi = &vmi.vmi
+		}
+
+		s.vms[*record.vm.Id] = record
+	}
+}
+
+func (t *transition) state() *transitionState {
+	if t == nil {
+		return nil
+	}
+
+	return &transitionState{Status: t.status, Polls: t.polls, Remove: t.remove}
+}
+
+func (t *transitionState) transition() *transition {
+	if t == nil {
+		return nil
+	}
+
+	return &transition{status: t.Status, polls: t.Polls, remove: t.Remove}
+}
+
+func sortedIds[T any](objects map[int32]T) []int32 {
+	ids := make([]int32, 0, len(objects))
+	for id := range objects {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	return ids
+}
@@ -0,0 +1,233 @@
+package mockapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
+)
+
+var testSeeds = Options{
+	Hosts: []HostSeed{{Name: "host-1", Status: client.Online}},
+	Vmis:  []string{"ubuntu-jammy"},
+}
+
+// openTestServer opens the state at path and returns a client backed by it.
+func openTestServer(t *testing.T, path string, options Options) (*Server, *client.ClientWithResponses) {
+	t.Helper()
+
+	server, err := Open(path, options)
+	if err != nil {
+		t.Fatalf("failed to open state: %s", err)
+	}
+
+	apiClient, err := client.NewClientWithResponses("http://crunchloop.mock", client.WithHTTPClient(server))
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+
+	return server, apiClient
+}
+
+func createTestVm(t *testing.T, apiClient *client.ClientWithResponses, name string) client.VirtualMachine {
+	t.Helper()
+
+	response, err := apiClient.CreateVmWithResponse(context.Background(), client.CreateVmJSONRequestBody{
+		Name:                    name,
+		Cores:                   1,
+		MemoryMegabytes:         512,
+		RootVolumeSizeGigabytes: 10,
+		VmiId:                   2,
+	})
+	if err != nil {
+		t.Fatalf("failed to create vm: %s", err)
+	}
+
+	if response.StatusCode() != http.StatusCreated {
+		t.Fatalf("failed to create vm: unexpected status code %d: %s", response.StatusCode(), response.Body)
+	}
+
+	return *response.JSON201
+}
+
+func assertStateFileExists(t *testing.T, path string, exists bool) {
+	t.Helper()
+
+	_, err := os.Stat(path)
+	if exists && err != nil {
+		t.Errorf("expected the state file to exist: %s", err)
+	}
+
+	if !exists && !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected the state file to be removed, got %v", err)
+	}
+}
+
+func TestOpen_missingState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	server, _ := openTestServer(t, path, testSeeds)
+
+	host, ok := server.hosts[1]
+	if !ok || *host.Name != "host-1" {
+		t.Errorf("expected host-1 to be seeded with id 1, got %v", server.hosts)
+	}
+
+	vmi, ok := server.vmis[2]
+	if !ok || *vmi.vmi.Name != "ubuntu-jammy" {
+		t.Errorf("expected ubuntu-jammy to be seeded with id 2, got %v", server.vmis)
+	}
+
+	// Nothing but the seeds exist, there's nothing to save yet.
+	assertStateFileExists(t, path, false)
+}
+
+func TestOpen_restoresState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	_, apiClient := openTestServer(t, path, testSeeds)
+	created := createTestVm(t, apiClient, "web")
+	assertStateFileExists(t, path, true)
+
+	// A new process opens the same state.
+	server, apiClient := openTestServer(t, path, testSeeds)
+
+	vm, ok := server.Vm(*created.Id)
+	if !ok {
+		t.Fatalf("expected vm %d to be restored", *created.Id)
+	}
+
+	if *vm.Name != "web" || *vm.Status != client.VirtualMachineStatusCreating {
+		t.Errorf("expected the vm to be restored as it was saved, got %s %s", *vm.Name, *vm.Status)
+	}
+
+	// The pending transition is restored too.
+	response, err := apiClient.GetVmWithResponse(context.Background(), *created.Id)
+	if err != nil {
+		t.Fatalf("failed to get vm: %s", err)
+	}
+
+	if *response.JSON200.Status != client.VirtualMachineStatusRunning {
+		t.Errorf("expected the vm to become running, got %s", *response.JSON200.Status)
+	}
+
+	// Identifiers continue where the saved state left them.
+	next := createTestVm(t, apiClient, "db")
+	if *next.Id <= *created.Nic.Id {
+		t.Errorf("expected the next vm id to follow %d, got %d", *created.Nic.Id, *next.Id)
+	}
+
+	// The vm shares the restored host again.
+	server.SetHostStatus(*created.Host.Id, client.Offline)
+	if vm, _ := server.Vm(*created.Id); *vm.Host.Status != client.Offline {
+		t.Errorf("expected the vm to share the restored host, got host status %s", *vm.Host.Status)
+	}
+}
+
+func TestOpen_seedsChanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	_, apiClient := openTestServer(t, path, testSeeds)
+	createTestVm(t, apiClient, "web")
+
+	seeds := Options{
+		Hosts: testSeeds.Hosts,
+		Vmis:  []string{"ubuntu-jammy", "ubuntu-noble"},
+	}
+
+	server, apiClient := openTestServer(t, path, seeds)
+
+	if len(server.vms) != 0 {
+		t.Errorf("expected the state saved with other seeds to be discarded, got %d vms", len(server.vms))
+	}
+
+	assertStateFileExists(t, path, false)
+
+	// Identifiers start over after the seeds.
+	vm := createTestVm(t, apiClient, "web")
+	if *vm.Id != 4 {
+		t.Errorf("expected the vm to get id 4, got %d", *vm.Id)
+	}
+}
+
+func TestOpen_invalidState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Open(path, testSeeds); err == nil {
+		t.Fatal("expected an error decoding the state")
+	}
+}
+
+func TestSave_removesStateWithOnlySeeds(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	_, apiClient := openTestServer(t, path, testSeeds)
+	vm := createTestVm(t, apiClient, "web")
+	assertStateFileExists(t, path, true)
+
+	response, err := apiClient.DeleteVmWithResponse(context.Background(), *vm.Id)
+	if err != nil || response.StatusCode() != http.StatusNoContent {
+		t.Fatalf("failed to delete vm: %v", err)
+	}
+
+	// The vm is deleting until it's observed again.
+	assertStateFileExists(t, path, true)
+
+	getResponse, err := apiClient.GetVmWithResponse(context.Background(), *vm.Id)
+	if err != nil {
+		t.Fatalf("failed to get vm: %s", err)
+	}
+
+	if getResponse.StatusCode() != http.StatusNotFound {
+		t.Fatalf("expected the vm to be deleted, got status code %d", getResponse.StatusCode())
+	}
+
+	assertStateFileExists(t, path, false)
+
+	// The next run starts over with deterministic identifiers.
+	_, apiClient = openTestServer(t, path, testSeeds)
+	vm = createTestVm(t, apiClient, "web")
+	if *vm.Id != 3 {
+		t.Errorf("expected the vm to get id 3, got %d", *vm.Id)
+	}
+}
+
+func TestSave_failureIsServerError(t *testing.T) {
+	// The directory of the state file doesn't exist, so it can't be saved.
+	path := filepath.Join(t.TempDir(), "missing", "state.json")
+
+	_, apiClient := openTestServer(t, path, testSeeds)
+
+	response, err := apiClient.CreateVmWithResponse(context.Background(), client.CreateVmJSONRequestBody{
+		Name:                    "web",
+		Cores:                   1,
+		MemoryMegabytes:         512,
+		RootVolumeSizeGigabytes: 10,
+		VmiId:                   2,
+	})
+	if err != nil {
+		t.Fatalf("failed to create vm: %s", err)
+	}
+
+	if response.StatusCode() != http.StatusInternalServerError {
+		t.Fatalf("expected status code %d, got %d: %s", http.StatusInternalServerError, response.StatusCode(), response.Body)
+	}
+
+	var body client.Error
+	if err := json.Unmarshal(response.Body, &body); err != nil {
+		t.Fatalf("failed to decode error: %s", err)
+	}
+
+	if body.Code != serverError {
+		t.Errorf("expected code %q, got %q", serverError, body.Code)
+	}
+}
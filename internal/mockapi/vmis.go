@@ -0,0 +1,122 @@
+package mockapi
+
+import (
+	"net/http"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
+)
+
+type vmiRecord struct {
+	vmi  client.VirtualMachineImage
+	next *transition
+}
+
+// AddVmi seeds an available vmi and returns it. When a vmi with the same name
+// exists, it's returned as is.
+func (s *Server) AddVmi(name string) client.VirtualMachineImage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, record := range s.vmis {
+		if *record.vmi.Name == name {
+			return record.vmi
+		}
+	}
+
+	record := s.createVmi(name, "", "")
+	record.vmi.Status = pointer(client.VirtualMachineImageStatusAvailable)
+	record.next = nil
+	s.seeded[*record.vmi.Id] = true
+
+	return record.vmi
+}
+
+func (s *Server) createVmi(name, url, sha256 string) *vmiRecord {
+	record := &vmiRecord{
+		vmi: client.VirtualMachineImage{
+			Id:     pointer(s.newId()),
+			Object: pointer("vmi"),
+			Name:   pointer(name),
+			Url:    pointer(url),
+			Sha256: pointer(sha256),
+			Status: pointer(client.VirtualMachineImageStatusCreating),
+		},
+		next: s.newTransition(string(client.VirtualMachineImageStatusAvailable)),
+	}
+	s.vmis[*record.vmi.Id] = record
+
+	return record
+}
+
+// observe applies the pending transition of the vmi once it's due.
+func (r *vmiRecord) observe() {
+	if r.next == nil || !r.next.advance() {
+		return
+	}
+
+	r.vmi.Status = pointer(client.VirtualMachineImageStatus(r.next.status))
+	r.next = nil
+}
+
+func (s *Server) routeVmis(w http.ResponseWriter, r *http.Request, segments []string) {
+	switch {
+	case len(segments) == 0:
+		if r.Method != http.MethodGet {
+			writeMethodNotAllowed(w)
+			return
+		}
+
+		s.listVmis(w, r)
+	case len(segments) == 1 && segments[0] == "proxmox":
+		if r.Method != http.MethodPost {
+			writeMethodNotAllowed(w)
+			return
+		}
+
+		s.createProxmoxVmi(w, r)
+	default:
+		writeNotFound(w, "route")
+	}
+}
+
+func (s *Server) listVmis(w http.ResponseWriter, r *http.Request) {
+	ids := make([]int32, 0, len(s.vmis))
+	for id := range s.vmis {
+		ids = append(ids, id)
+	}
+
+	ids, hasMore, ok := page(w, r, ids)
+	if !ok {
+		return
+	}
+
+	data := make([]client.VirtualMachineImage, 0, len(ids))
+	for _, id := range ids {
+		s.vmis[id].observe()
+		data = append(data, s.vmis[id].vmi)
+	}
+
+	writeJSON(w, http.StatusOK, client.VirtualMachineImageCollection{
+		Object:  pointer("list"),
+		HasMore: &hasMore,
+		Data:    &data,
+	})
+}
+
+func (s *Server) createProxmoxVmi(w http.ResponseWriter, r *http.Request) {
+	var body client.CreateProxmoxVmiJSONRequestBody
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	var details []client.ErrorDetail
+	details = validateRequired(details, "name", body.Name)
+	details = validateRequired(details, "url", body.Url)
+	details = validateRequired(details, "sha256", body.Sha256)
+	if len(details) > 0 {
+		writeInputError(w, details...)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, s.createVmi(body.Name, body.Url, body.Sha256).vmi)
+}
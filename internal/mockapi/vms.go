@@ -0,0 +1,402 @@
+package mockapi
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
+)
+
+type vmRecord struct {
+	vm     client.VirtualMachine
+	next   *transition
+	leases int
+}
+
+// Vm returns the vm with the given identifier as currently stored, without
+// advancing its transitions. It's meant for assertions, e.g. checking a vm
+// was destroyed.
+func (s *Server) Vm(id int32) (client.VirtualMachine, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.vms[id]
+	if !ok {
+		return client.VirtualMachine{}, false
+	}
+
+	return record.vm, true
+}
+
+// SetVmStatus changes the status of a vm behind the provider's back, e.g. to
+// simulate a vm stopped from the Proxmox console. It returns false when the vm
+// doesn't exist.
+func (s *Server) SetVmStatus(id int32, status client.VirtualMachineStatus) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.vms[id]
+	if !ok {
+		return false
+	}
+
+	record.vm.Status = &status
+	record.next = nil
+
+	return true
+}
+
+// RemoveVm deletes a vm behind the provider's back, e.g. to simulate a vm
+// destroyed from the Proxmox console. It returns false when the vm doesn't
+// exist.
+func (s *Server) RemoveVm(id int32) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.vms[id]; !ok {
+		return false
+	}
+
+	delete(s.vms, id)
+
+	return true
+}
+
+// observeVm applies the pending transition of the vm once it's due, it
+// returns false when the vm was removed.
+func (s *Server) observeVm(record *vmRecord) bool {
+	if record.next == nil || !record.next.advance() {
+		return true
+	}
+
+	next := record.next
+	record.next = nil
+
+	if next.remove {
+		delete(s.vms, *record.vm.Id)
+		return false
+	}
+
+	status := client.VirtualMachineStatus(next.status)
+	record.vm.Status = &status
+
+	// The network interface leases an address when the vm boots, a new one
+	// every time, and the root volume is attached on the first boot.
+	if status == client.VirtualMachineStatusRunning && *record.vm.Nic.IpAddress == "" {
+		id := *record.vm.Id
+		record.vm.Nic.IpAddress = pointer(fmt.Sprintf("10.%d.%d.%d", record.leases%256, id/250, id%250+2))
+		record.vm.RootVolume.Status = pointer(client.InUse)
+		record.leases++
+	}
+
+	return true
+}
+
+func (s *Server) routeVms(w http.ResponseWriter, r *http.Request, segments []string) {
+	switch {
+	case len(segments) == 0:
+		switch r.Method {
+		case http.MethodGet:
+			s.listVms(w, r)
+		case http.MethodPost:
+			s.createVm(w, r)
+		default:
+			writeMethodNotAllowed(w)
+		}
+	case len(segments) == 1:
+		id, ok := parseId(w, segments[0], "vm")
+		if !ok {
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			s.getVm(w, id)
+		case http.MethodPut:
+			s.updateVm(w, r, id)
+		case http.MethodDelete:
+			s.deleteVm(w, id)
+		default:
+			writeMethodNotAllowed(w)
+		}
+	case len(segments) == 2:
+		id, ok := parseId(w, segments[0], "vm")
+		if !ok {
+			return
+		}
+
+		if r.Method != http.MethodPost {
+			writeMethodNotAllowed(w)
+			return
+		}
+
+		s.vmAction(w, id, segments[1])
+	default:
+		writeNotFound(w, "route")
+	}
+}
+
+func (s *Server) listVms(w http.ResponseWriter, r *http.Request) {
+	ids := make([]int32, 0, len(s.vms))
+	for id, record := range s.vms {
+		if s.observeVm(record) {
+			ids = append(ids, id)
+		}
+	}
+
+	ids, hasMore, ok := page(w, r, ids)
+	if !ok {
+		return
+	}
+
+	data := make([]client.VirtualMachine, 0, len(ids))
+	for _, id := range ids {
+		data = append(data, s.vms[id].vm)
+	}
+
+	writeJSON(w, http.StatusOK, client.VirtualMachineCollection{
+		Object:  pointer("list"),
+		HasMore: &hasMore,
+		Data:    &data,
+	})
+}
+
+func (s *Server) createVm(w http.ResponseWriter, r *http.Request) {
+	var body client.CreateVmJSONRequestBody
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	var details []client.ErrorDetail
+	details = validateRequired(details, "name", body.Name)
+	details = validateRange(details, "cores", body.Cores, client.CreateVmCoresMinimum, client.CreateVmCoresMaximum)
+	details = validateRange(details, "memory_megabytes", body.MemoryMegabytes, client.CreateVmMemoryMegabytesMinimum, client.CreateVmMemoryMegabytesMaximum)
+	details = validateRange(details, "root_volume_size_gigabytes", body.RootVolumeSizeGigabytes, client.CreateVmRootVolumeSizeGigabytesMinimum, client.CreateVmRootVolumeSizeGigabytesMaximum)
+
+	vmi, ok := s.vmis[body.VmiId]
+	if !ok {
+		details = append(details, client.ErrorDetail{Field: "vmi_id", Message: "does not exist"})
+	} else if vmi.observe(); *vmi.vmi.Status != client.VirtualMachineImageStatusAvailable {
+		details = append(details, client.ErrorDetail{Field: "vmi_id", Message: fmt.Sprintf("is %s", *vmi.vmi.Status)})
+	}
+
+	host, hostDetail := s.scheduleHost(body.HostId)
+	if hostDetail != nil {
+		details = append(details, *hostDetail)
+	}
+
+	if len(details) > 0 {
+		writeInputError(w, details...)
+		return
+	}
+
+	id := s.newId()
+	record := &vmRecord{
+		vm: client.VirtualMachine{
+			Id:          &id,
+			Object:      pointer("vm"),
+			Name:        pointer(body.Name),
+			Status:      pointer(client.VirtualMachineStatusCreating),
+			Cores:       pointer(body.Cores),
+			MemoryBytes: pointer(int64(body.MemoryMegabytes) * 1024 * 1024),
+			Vmi:         &vmi.vmi,
+			Host:        host,
+			RootVolume: &client.Volume{
+				Id:        pointer(s.newId()),
+				Object:    pointer("volume"),
+				Name:      pointer(fmt.Sprintf("%s-root", body.Name)),
+				SizeBytes: pointer(int64(body.RootVolumeSizeGigabytes) * 1024 * 1024 * 1024),
+				Status:    pointer(client.Creating),
+			},
+			Nic: &client.NetworkInterface{
+				Id:        pointer(s.newId()),
+				Object:    pointer("nic"),
+				Dhcp:      pointer(true),
+				IpAddress: pointer(""),
+			},
+		},
+		next: s.newTransition(string(client.VirtualMachineStatusRunning)),
+	}
+	s.vms[id] = record
+
+	writeJSON(w, http.StatusCreated, record.vm)
+}
+
+// scheduleHost returns the requested host, or the online host with the fewest
+// vms when none was requested.
+func (s *Server) scheduleHost(hostId *int32) (*client.Host, *client.ErrorDetail) {
+	if hostId != nil {
+		host, ok := s.hosts[*hostId]
+		if !ok {
+			return nil, &client.ErrorDetail{Field: "host_id", Message: "does not exist"}
+		}
+
+		if *host.Status != client.Online {
+			return nil, &client.ErrorDetail{Field: "host_id", Message: fmt.Sprintf("is %s", *host.Status)}
+		}
+
+		return host, nil
+	}
+
+	load := map[int32]int{}
+	for _, record := range s.vms {
+		load[*record.vm.Host.Id]++
+	}
+
+	var candidates []*client.Host
+	for _, host := range s.hosts {
+		if *host.Status == client.Online {
+			candidates = append(candidates, host)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil, &client.ErrorDetail{Field: "host_id", Message: "no online host available"}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if load[*candidates[i].Id] != load[*candidates[j].Id] {
+			return load[*candidates[i].Id] < load[*candidates[j].Id]
+		}
+
+		return *candidates[i].Id < *candidates[j].Id
+	})
+
+	return candidates[0], nil
+}
+
+// findVm returns the vm with the given identifier, writing a not found error
+// when it doesn't exist.
+func (s *Server) findVm(w http.ResponseWriter, id int32) (*vmRecord, bool) {
+	record, ok := s.vms[id]
+	if !ok || !s.observeVm(record) {
+		writeNotFound(w, "vm")
+		return nil, false
+	}
+
+	return record, true
+}
+
+func (s *Server) getVm(w http.ResponseWriter, id int32) {
+	record, ok := s.findVm(w, id)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, record.vm)
+}
+
+func (s *Server) updateVm(w http.ResponseWriter, r *http.Request, id int32) {
+	record, ok := s.findVm(w, id)
+	if !ok {
+		return
+	}
+
+	var body client.UpdateVmJSONRequestBody
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	var details []client.ErrorDetail
+	if body.Cores != nil {
+		details = validateRange(details, "cores", *body.Cores, client.UpdateVmCoresMinimum, client.UpdateVmCoresMaximum)
+	}
+
+	if body.MemoryMegabytes != nil {
+		details = validateRange(details, "memory_megabytes", *body.MemoryMegabytes, client.UpdateVmMemoryMegabytesMinimum, client.UpdateVmMemoryMegabytesMaximum)
+	}
+
+	if len(details) > 0 {
+		writeInputError(w, details...)
+		return
+	}
+
+	if !s.vmIsSettled(w, record) {
+		return
+	}
+
+	if body.Cores != nil {
+		record.vm.Cores = pointer(*body.Cores)
+	}
+
+	if body.MemoryMegabytes != nil {
+		record.vm.MemoryBytes = pointer(int64(*body.MemoryMegabytes) * 1024 * 1024)
+	}
+
+	// Updating the vm restarts it, releasing its DHCP lease.
+	if *record.vm.Status == client.VirtualMachineStatusRunning && *record.vm.Nic.Dhcp {
+		record.vm.Nic.IpAddress = pointer("")
+	}
+
+	record.next = s.newTransition(string(*record.vm.Status))
+	record.vm.Status = pointer(client.VirtualMachineStatusUpdating)
+
+	writeJSON(w, http.StatusOK, record.vm)
+}
+
+func (s *Server) deleteVm(w http.ResponseWriter, id int32) {
+	record, ok := s.findVm(w, id)
+	if !ok {
+		return
+	}
+
+	if *record.vm.Status != client.VirtualMachineStatusDeleting {
+		record.vm.Status = pointer(client.VirtualMachineStatusDeleting)
+		record.next = s.newTransition("")
+		record.next.remove = true
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) vmAction(w http.ResponseWriter, id int32, action string) {
+	if action != "start" && action != "stop" && action != "reboot" {
+		writeNotFound(w, "route")
+		return
+	}
+
+	record, ok := s.findVm(w, id)
+	if !ok || !s.vmIsSettled(w, record) {
+		return
+	}
+
+	status := *record.vm.Status
+
+	switch action {
+	case "start":
+		if status != client.VirtualMachineStatusRunning {
+			record.next = s.newTransition(string(client.VirtualMachineStatusRunning))
+		}
+	case "stop":
+		if status != client.VirtualMachineStatusStopped {
+			record.next = s.newTransition(string(client.VirtualMachineStatusStopped))
+		}
+	case "reboot":
+		if status != client.VirtualMachineStatusRunning {
+			writeInputError(w, client.ErrorDetail{Field: "status", Message: fmt.Sprintf("must be running to reboot the vm, it is %s", status)})
+			return
+		}
+
+		// The spec has no rebooting status and, like Proxmox, the vm keeps
+		// reporting running while the guest reboots.
+		record.next = s.newTransition(string(client.VirtualMachineStatusRunning))
+	}
+
+	writeJSON(w, http.StatusOK, record.vm)
+}
+
+// vmIsSettled writes an input error when the vm is still transitioning, the
+// mock rejects operations on a vm until its current operation finishes.
+func (s *Server) vmIsSettled(w http.ResponseWriter, record *vmRecord) bool {
+	if record.next == nil {
+		return true
+	}
+
+	writeInputError(w, client.ErrorDetail{
+		Field:   "status",
+		Message: fmt.Sprintf("vm is %s, wait until the current operation finishes", *record.vm.Status),
+	})
+
+	return false
+}
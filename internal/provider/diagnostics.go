@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/services"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// addApiError appends diagnostics describing an error returned by the
+// service layer.
+func addApiError(diags *diag.Diagnostics, err error) {
+	addApiErrorWithAttributes(diags, err, nil)
+}
+
+// addApiErrorWithAttributes is addApiError for requests built from resource
+// attributes. Input errors reported by the API for one of the request fields
+// in attributes are attached to the matching attribute, any other field is
+// reported without an attribute since it may not exist in the schema.
+func addApiErrorWithAttributes(diags *diag.Diagnostics, err error, attributes map[string]path.Path) {
+	var apiErr *services.ApiError
+	if !errors.As(err, &apiErr) {
+		diags.AddError("API Error", err.Error())
+		return
+	}
+
+	switch {
+	case services.IsUnauthorized(err):
+		diags.AddError(
+			fmt.Sprintf("Authentication Error (HTTP %d %s)", apiErr.StatusCode, http.StatusText(apiErr.StatusCode)),
+			fmt.Sprintf("The Crunchloop API rejected the request: %s.\n\n"+
+				"Set the provider api_token attribute or the CRUNCHLOOP_API_TOKEN environment variable to a token allowed to perform this operation.", apiErr),
+		)
+	case services.IsInputError(err) && len(apiErr.Details) > 0:
+		for _, detail := range apiErr.Details {
+			attribute, ok := attributes[detail.Field]
+			if ok {
+				diags.AddAttributeError(attribute, "Invalid Input", detail.Message)
+				continue
+			}
+
+			if detail.Field == "" {
+				diags.AddError("Invalid Input", detail.Message)
+				continue
+			}
+
+			diags.AddError("Invalid Input", fmt.Sprintf("%s %s", detail.Field, detail.Message))
+		}
+	case services.IsInputError(err):
+		diags.AddError("Invalid Input", apiErr.Error())
+	case services.IsNotFound(err):
+		diags.AddError("Not Found", apiErr.Error())
+	default:
+		diags.AddError("API Error", apiErr.Error())
+	}
+}
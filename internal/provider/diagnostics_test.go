@@ -0,0 +1,179 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/services"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+func TestAddApiErrorWithAttributes(t *testing.T) {
+	attributes := map[string]path.Path{
+		"memory_megabytes": path.Root("memory_megabytes"),
+	}
+
+	inputError := func(details ...client.ErrorDetail) error {
+		return &services.ApiError{
+			Operation:  "create vm",
+			StatusCode: http.StatusBadRequest,
+			Code:       client.InputError,
+			Message:    "invalid input",
+			Details:    details,
+		}
+	}
+
+	type expectedDiagnostic struct {
+		summary string
+		detail  string
+		path    *path.Path
+	}
+
+	memoryPath := path.Root("memory_megabytes")
+
+	cases := []struct {
+		name     string
+		err      error
+		expected []expectedDiagnostic
+	}{
+		{
+			name: "known field",
+			err:  inputError(client.ErrorDetail{Field: "memory_megabytes", Message: "must be less than or equal to 1024"}),
+			expected: []expectedDiagnostic{
+				{summary: "Invalid Input", detail: "must be less than or equal to 1024", path: &memoryPath},
+			},
+		},
+		{
+			name: "unknown field",
+			err:  inputError(client.ErrorDetail{Field: "limit", Message: "must be between 1 and 100"}),
+			expected: []expectedDiagnostic{
+				{summary: "Invalid Input", detail: "limit must be between 1 and 100"},
+			},
+		},
+		{
+			name: "empty field",
+			err:  inputError(client.ErrorDetail{Message: "vm can't be created"}),
+			expected: []expectedDiagnostic{
+				{summary: "Invalid Input", detail: "vm can't be created"},
+			},
+		},
+		{
+			name: "several fields",
+			err: fmt.Errorf("wrapped: %w", inputError(
+				client.ErrorDetail{Field: "memory_megabytes", Message: "must be less than or equal to 1024"},
+				client.ErrorDetail{Field: "status", Message: "is stopped"},
+			)),
+			expected: []expectedDiagnostic{
+				{summary: "Invalid Input", detail: "must be less than or equal to 1024", path: &memoryPath},
+				{summary: "Invalid Input", detail: "status is stopped"},
+			},
+		},
+		{
+			name: "input error without details",
+			err:  inputError(),
+			expected: []expectedDiagnostic{
+				{summary: "Invalid Input", detail: "failed to create vm: invalid input"},
+			},
+		},
+		{
+			name: "unauthorized",
+			err:  &services.ApiError{Operation: "create vm", StatusCode: http.StatusUnauthorized},
+			expected: []expectedDiagnostic{
+				{summary: "Authentication Error (HTTP 401 Unauthorized)"},
+			},
+		},
+		{
+			name: "forbidden",
+			err:  &services.ApiError{Operation: "create vm", StatusCode: http.StatusForbidden, Code: client.InputError},
+			expected: []expectedDiagnostic{
+				{summary: "Authentication Error (HTTP 403 Forbidden)"},
+			},
+		},
+		{
+			name: "not found",
+			err:  &services.ApiError{Operation: "get vm", StatusCode: http.StatusNotFound, Code: client.RecordNotFound, Message: "vm not found"},
+			expected: []expectedDiagnostic{
+				{summary: "Not Found", detail: "failed to get vm: vm not found"},
+			},
+		},
+		{
+			name: "unexpected status",
+			err:  &services.ApiError{Operation: "get vm", StatusCode: http.StatusBadGateway},
+			expected: []expectedDiagnostic{
+				{summary: "API Error", detail: "failed to get vm: unexpected status code 502"},
+			},
+		},
+		{
+			name: "not an api error",
+			err:  errors.New("connection refused"),
+			expected: []expectedDiagnostic{
+				{summary: "API Error", detail: "connection refused"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			addApiErrorWithAttributes(&diags, c.err, attributes)
+
+			if len(diags) != len(c.expected) {
+				t.Fatalf("expected %d diagnostics, got %d: %v", len(c.expected), len(diags), diags)
+			}
+
+			for i, expected := range c.expected {
+				actual := diags[i]
+
+				if actual.Severity() != diag.SeverityError {
+					t.Errorf("expected diagnostic %d to be an error, got %s", i, actual.Severity())
+				}
+
+				if actual.Summary() != expected.summary {
+					t.Errorf("expected diagnostic %d summary %q, got %q", i, expected.summary, actual.Summary())
+				}
+
+				// Authentication errors explain how to configure the token,
+				// only their summary is checked.
+				if expected.detail != "" && actual.Detail() != expected.detail {
+					t.Errorf("expected diagnostic %d detail %q, got %q", i, expected.detail, actual.Detail())
+				}
+
+				withPath, ok := actual.(diag.DiagnosticWithPath)
+				switch {
+				case expected.path == nil && ok:
+					t.Errorf("expected diagnostic %d without attribute, got %s", i, withPath.Path())
+				case expected.path != nil && !ok:
+					t.Errorf("expected diagnostic %d for attribute %s, got none", i, expected.path)
+				case expected.path != nil && !withPath.Path().Equal(*expected.path):
+					t.Errorf("expected diagnostic %d for attribute %s, got %s", i, expected.path, withPath.Path())
+				}
+			}
+		})
+	}
+}
+
+func TestAddApiError_withoutAttributes(t *testing.T) {
+	var diags diag.Diagnostics
+	addApiError(&diags, &services.ApiError{
+		Operation:  "list hosts",
+		StatusCode: http.StatusBadRequest,
+		Code:       client.InputError,
+		Details:    []client.ErrorDetail{{Field: "name", Message: "can't be blank"}},
+	})
+
+	if len(diags) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d: %v", len(diags), diags)
+	}
+
+	if _, ok := diags[0].(diag.DiagnosticWithPath); ok {
+		t.Errorf("expected the input error not to be attached to an attribute, got %v", diags[0])
+	}
+
+	if detail := diags[0].Detail(); detail != "name can't be blank" {
+		t.Errorf("expected detail %q, got %q", "name can't be blank", detail)
+	}
+}
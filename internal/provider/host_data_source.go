@@ -6,14 +6,19 @@ import (
 	"strconv"
 
 	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/services"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/tracing"
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ datasource.DataSource = &HostDataSource{}
+var _ datasource.DataSourceWithConfigValidators = &HostDataSource{}
 
 func NewHostDataSource() datasource.DataSource {
 	return &HostDataSource{}
@@ -21,13 +26,15 @@ func NewHostDataSource() datasource.DataSource {
 
 // HostDataSource defines the data source implementation.
 type HostDataSource struct {
-	client *client.ClientWithResponses
+	service *services.HostService
 }
 
 // HostDataSourceModel describes the data source data model.
 type HostDataSourceModel struct {
-	Id   types.String `tfsdk:"id"`
-	Name types.String `tfsdk:"name"`
+	Id            types.String `tfsdk:"id"`
+	Name          types.String `tfsdk:"name"`
+	Status        types.String `tfsdk:"status"`
+	RequireOnline types.Bool   `tfsdk:"require_online"`
 }
 
 func (d *HostDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -37,72 +44,133 @@ func (d *HostDataSource) Metadata(ctx context.Context, req datasource.MetadataRe
 func (d *HostDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		// This description is used by the documentation generator and the language server.
-		MarkdownDescription: "Host data source",
+		MarkdownDescription: "Host data source, looks up an existing Host by `id` or `name`",
 
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
-				MarkdownDescription: "Host identifier",
+				MarkdownDescription: "Host identifier, conflicts with `name`",
+				Optional:            true,
 				Computed:            true,
 			},
 			"name": schema.StringAttribute{
-				MarkdownDescription: "Host name",
-				Required:            true,
+				MarkdownDescription: "Host name, conflicts with `id`",
+				Optional:            true,
+				Computed:            true,
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "Host status, either `online` or `offline`",
+				Computed:            true,
+			},
+			"require_online": schema.BoolAttribute{
+				MarkdownDescription: "Fail when the Host is not `online`, so Vms aren't scheduled onto an unavailable Host. Defaults to `false`",
+				Optional:            true,
 			},
 		},
 	}
 }
 
+func (d *HostDataSource) ConfigValidators(ctx context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(
+			path.MatchRoot("id"),
+			path.MatchRoot("name"),
+		),
+	}
+}
+
 func (d *HostDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*client.ClientWithResponses)
+	providerData, ok := req.ProviderData.(*CrunchloopProviderData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *client.ClientWithResponses, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *CrunchloopProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	d.client = client
+	d.service = services.NewHostService(providerData.Client)
 }
 
 func (d *HostDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, span := tracing.Start(ctx, "data.crunchloop_host", "Read")
+	defer tracing.End(span, &resp.Diagnostics)
+
 	var data HostDataSourceModel
 
 	// Read Terraform configuration data into the model
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
-
-	response, err := d.client.ListHostsWithResponse(ctx)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"API Error",
-			fmt.Sprintf("Unable to read host, got error: %s", err),
-		)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	for _, host := range *response.JSON200.Data {
-		if *host.Name == data.Name.ValueString() {
-			data.Id = types.StringValue(strconv.Itoa(int(*host.Id)))
-			data.Name = types.StringValue(*host.Name)
+	var host *client.Host
 
-			tflog.Trace(ctx, "read host data source")
+	if !data.Id.IsNull() {
+		id, err := strconv.Atoi(data.Id.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("id"),
+				"Invalid Host Identifier",
+				fmt.Sprintf("Host identifier must be a number, got: %s", data.Id.ValueString()),
+			)
+			return
+		}
 
-			// Save data into Terraform state
-			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		host, err = d.service.GetHost(ctx, int32(id))
+		if err != nil {
+			addApiError(&resp.Diagnostics, err)
+			return
+		}
+	} else {
+		iterator := d.service.IterateHosts()
+		for iterator.Next(ctx) {
+			if value := iterator.Value(); value.Name != nil && *value.Name == data.Name.ValueString() {
+				host = &value
+				break
+			}
+		}
 
+		if err := iterator.Err(); err != nil {
+			addApiError(&resp.Diagnostics, err)
+			return
+		}
+
+		if host == nil {
+			resp.Diagnostics.AddError(
+				"API Error",
+				fmt.Sprintf("Host with name %s was not found", data.Name.ValueString()),
+			)
 			return
 		}
 	}
 
-	resp.Diagnostics.AddError(
-		"API Error",
-		fmt.Sprintf("Host with name %s was not found", data.Name.ValueString()),
-	)
+	if data.RequireOnline.ValueBool() && (host.Status == nil || *host.Status != client.Online) {
+		status := "unknown"
+		if host.Status != nil {
+			status = string(*host.Status)
+		}
+
+		resp.Diagnostics.AddAttributeError(
+			path.Root("require_online"),
+			"Host Not Online",
+			fmt.Sprintf("Host %s is required to be online, but its status is: %s", types.StringPointerValue(host.Name).ValueString(), status),
+		)
+		return
+	}
+
+	data.Id = idPointerValue(host.Id)
+	data.Name = types.StringPointerValue(host.Name)
+	data.Status = types.StringPointerValue((*string)(host.Status))
+
+	tflog.Trace(ctx, "read host data source")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -0,0 +1,64 @@
+package provider_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/acctest"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccHostDataSource(t *testing.T) {
+	server, url := acctest.NewServer(t)
+	online := server.AddHost("host-1", client.Online)
+	offline := server.AddHost("host-2", client.Offline)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Lookup by name
+			{
+				Config: acctest.ProviderConfig(url) + `
+data "crunchloop_host" "test" {
+  name = "host-1"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.crunchloop_host.test", "id", fmt.Sprint(*online.Id)),
+					resource.TestCheckResourceAttr("data.crunchloop_host.test", "status", "online"),
+				),
+			},
+			// Lookup by id
+			{
+				Config: acctest.ProviderConfig(url) + fmt.Sprintf(`
+data "crunchloop_host" "test" {
+  id = "%d"
+}
+`, *offline.Id),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.crunchloop_host.test", "name", "host-2"),
+					resource.TestCheckResourceAttr("data.crunchloop_host.test", "status", "offline"),
+				),
+			},
+			{
+				Config: acctest.ProviderConfig(url) + `
+data "crunchloop_host" "test" {
+  name           = "host-2"
+  require_online = true
+}
+`,
+				ExpectError: regexp.MustCompile("Host Not Online"),
+			},
+			{
+				Config: acctest.ProviderConfig(url) + `
+data "crunchloop_host" "test" {
+  name = "host-3"
+}
+`,
+				ExpectError: regexp.MustCompile("not found"),
+			},
+		},
+	})
+}
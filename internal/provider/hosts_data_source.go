@@ -0,0 +1,160 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/services"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/tracing"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &HostsDataSource{}
+
+func NewHostsDataSource() datasource.DataSource {
+	return &HostsDataSource{}
+}
+
+// HostsDataSource defines the data source implementation.
+type HostsDataSource struct {
+	service *services.HostService
+}
+
+// HostsDataSourceModel describes the data source data model. The list data
+// sources have no object type filter, every object listed by an endpoint has
+// the same `object` type, e.g. `host`.
+type HostsDataSourceModel struct {
+	NameRegex types.String  `tfsdk:"name_regex"`
+	Status    types.String  `tfsdk:"status"`
+	Hosts     []vmHostModel `tfsdk:"hosts"`
+}
+
+func (d *HostsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_hosts"
+}
+
+func (d *HostsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Hosts data source, lists every Host matching the given filters. There is no object type filter, the API only lists hosts here",
+
+		Attributes: map[string]schema.Attribute{
+			"name_regex": schema.StringAttribute{
+				MarkdownDescription: "Regular expression the Host name must match",
+				Optional:            true,
+				Validators: []validator.String{
+					regexpValidator{},
+				},
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "Only return Hosts with this status, one of `online` or `offline`",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(string(client.Online), string(client.Offline)),
+				},
+			},
+			"hosts": schema.ListNestedAttribute{
+				MarkdownDescription: "Matching Hosts, sorted by id, i.e. in creation order",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "Host identifier",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Host name",
+							Computed:            true,
+						},
+						"status": schema.StringAttribute{
+							MarkdownDescription: "Host status",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *HostsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*CrunchloopProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *CrunchloopProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.service = services.NewHostService(providerData.Client)
+}
+
+func (d *HostsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, span := tracing.Start(ctx, "data.crunchloop_hosts", "Read")
+	defer tracing.End(span, &resp.Diagnostics)
+
+	var data HostsDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	nameRegex, err := regexp.Compile(data.NameRegex.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Regular Expression", err.Error())
+		return
+	}
+
+	hosts, err := d.service.ListHosts(ctx)
+	if err != nil {
+		addApiError(&resp.Diagnostics, err)
+		return
+	}
+
+	// Ids are assigned incrementally, sorting by id lists the hosts in the
+	// order they were created and keeps the order stable across renames.
+	sort.SliceStable(hosts, func(i, j int) bool {
+		return idLess(hosts[i].Id, hosts[j].Id)
+	})
+
+	data.Hosts = []vmHostModel{}
+	for _, host := range hosts {
+		if !nameRegex.MatchString(types.StringPointerValue(host.Name).ValueString()) {
+			continue
+		}
+
+		if !data.Status.IsNull() && types.StringPointerValue((*string)(host.Status)).ValueString() != data.Status.ValueString() {
+			continue
+		}
+
+		data.Hosts = append(data.Hosts, vmHostModel{
+			Id:     idPointerValue(host.Id),
+			Name:   types.StringPointerValue(host.Name),
+			Status: types.StringPointerValue((*string)(host.Status)),
+		})
+	}
+
+	tflog.Trace(ctx, "read hosts data source", map[string]interface{}{"count": len(data.Hosts)})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
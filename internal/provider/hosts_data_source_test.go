@@ -0,0 +1,48 @@
+package provider_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/acctest"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccHostsDataSource(t *testing.T) {
+	server, url := acctest.NewServer(t)
+	second := server.AddHost("pve-2", client.Online)
+	server.AddHost("pve-1", client.Offline)
+	first := server.AddHost("pve-0", client.Online)
+	server.AddHost("other", client.Online)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(url) + `
+data "crunchloop_hosts" "test" {
+  name_regex = "^pve-"
+  status     = "online"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.crunchloop_hosts.test", "hosts.#", "2"),
+					resource.TestCheckResourceAttr("data.crunchloop_hosts.test", "hosts.0.id", fmt.Sprint(*second.Id)),
+					resource.TestCheckResourceAttr("data.crunchloop_hosts.test", "hosts.1.id", fmt.Sprint(*first.Id)),
+				),
+			},
+			// Without filters every host is listed.
+			{
+				Config: acctest.ProviderConfig(url) + `
+data "crunchloop_hosts" "test" {}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.crunchloop_hosts.test", "hosts.#", "4"),
+					resource.TestCheckResourceAttr("data.crunchloop_hosts.test", "hosts.1.name", "pve-1"),
+					resource.TestCheckResourceAttr("data.crunchloop_hosts.test", "hosts.1.status", "offline"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,34 @@
+package provider
+
+import (
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// vmHostModel describes a host, either listed by the hosts data source or
+// the one a vm runs on.
+type vmHostModel struct {
+	Id     types.String `tfsdk:"id"`
+	Name   types.String `tfsdk:"name"`
+	Status types.String `tfsdk:"status"`
+}
+
+// idPointerValue converts an optional API identifier into the string
+// identifiers used across the provider.
+func idPointerValue(id *int32) types.String {
+	if id == nil {
+		return types.StringNull()
+	}
+
+	return types.StringValue(strconv.Itoa(int(*id)))
+}
+
+// idLess orders optional API identifiers, objects without one come first.
+func idLess(a, b *int32) bool {
+	if a == nil || b == nil {
+		return a == nil && b != nil
+	}
+
+	return *a < *b
+}
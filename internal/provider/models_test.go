@@ -0,0 +1,30 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestIdLess(t *testing.T) {
+	one, two := int32(1), int32(2)
+
+	cases := []struct {
+		name     string
+		a, b     *int32
+		expected bool
+	}{
+		{name: "ascending", a: &one, b: &two, expected: true},
+		{name: "descending", a: &two, b: &one, expected: false},
+		{name: "equal", a: &one, b: &one, expected: false},
+		{name: "missing first", a: nil, b: &one, expected: true},
+		{name: "missing second", a: &one, b: nil, expected: false},
+		{name: "both missing", a: nil, b: nil, expected: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if actual := idLess(c.a, c.b); actual != c.expected {
+				t.Errorf("expected idLess to be %t, got %t", c.expected, actual)
+			}
+		})
+	}
+}
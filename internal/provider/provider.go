@@ -2,18 +2,53 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
 
 	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/mockapi"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/services"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/transport"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
+// defaultOperationTimeout is used when neither the provider nor the resource
+// configure a timeout.
+const defaultOperationTimeout = 5 * time.Minute
+
+// defaultMaxRetries is the number of times transient API errors are retried
+// when the provider doesn't configure max_retries.
+const defaultMaxRetries = 3
+
+// Provider modes, mock replaces the Crunchloop API with an embedded in-memory
+// backend.
+const (
+	providerModeLive = "live"
+	providerModeMock = "mock"
+)
+
+// defaultMockStateFile is where the mock backend keeps its state between
+// Terraform commands when mock.state_file isn't configured.
+const defaultMockStateFile = "crunchloop-mock-state.json"
+
+// mockUrl is the base url of the client in mock mode, requests never leave
+// the provider process.
+const mockUrl = "http://crunchloop.mock"
+
 // Ensure CrunchloopProvider satisfies various provider interfaces.
 var _ provider.Provider = &CrunchloopProvider{}
 var _ provider.ProviderWithFunctions = &CrunchloopProvider{}
@@ -26,9 +61,43 @@ type CrunchloopProvider struct {
 	version string
 }
 
+// CrunchloopProviderData is handed to data sources and resources as
+// ProviderData once the provider is configured.
+type CrunchloopProviderData struct {
+	// Client is the Crunchloop API, either the generated HTTP client or the
+	// mock backend. Services only depend on the services.Api interface, so
+	// it can be decorated without changing data sources and resources.
+	Client services.Api
+
+	// DefaultTimeout bounds create, update and delete operations when the
+	// resource doesn't configure its own timeouts.
+	DefaultTimeout time.Duration
+}
+
 // crunchloopProviderModel describes the provider data model.
 type crunchloopProviderModel struct {
-	Url types.String `tfsdk:"url"`
+	Url            types.String `tfsdk:"url"`
+	ApiToken       types.String `tfsdk:"api_token"`
+	DefaultTimeout types.String `tfsdk:"default_timeout"`
+	MaxRetries     types.Int64  `tfsdk:"max_retries"`
+	Mode           types.String `tfsdk:"mode"`
+	Mock           *mockModel   `tfsdk:"mock"`
+}
+
+// mockModel describes the mock backend configuration.
+type mockModel struct {
+	StateFile types.String    `tfsdk:"state_file"`
+	Hosts     []mockHostModel `tfsdk:"hosts"`
+	Vmis      []mockVmiModel  `tfsdk:"vmis"`
+}
+
+type mockHostModel struct {
+	Name   types.String `tfsdk:"name"`
+	Status types.String `tfsdk:"status"`
+}
+
+type mockVmiModel struct {
+	Name types.String `tfsdk:"name"`
 }
 
 func (p *CrunchloopProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -40,8 +109,78 @@ func (p *CrunchloopProvider) Schema(ctx context.Context, req provider.SchemaRequ
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"url": schema.StringAttribute{
-				MarkdownDescription: "URL for the Crunchloop instance",
-				Required:            true,
+				MarkdownDescription: "URL for the Crunchloop instance. May also be provided via the `CRUNCHLOOP_URL` environment variable.",
+				Optional:            true,
+			},
+			"api_token": schema.StringAttribute{
+				MarkdownDescription: "API token used to authenticate with the Crunchloop instance. May also be provided via the `CRUNCHLOOP_API_TOKEN` environment variable.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"default_timeout": schema.StringAttribute{
+				MarkdownDescription: "Default timeout for resource operations that wait for the Crunchloop instance, e.g. `10m`. " +
+					"Resources can override it through their `timeouts` block. Defaults to `5m`. May also be provided via the `CRUNCHLOOP_DEFAULT_TIMEOUT` environment variable.",
+				Optional: true,
+			},
+			"max_retries": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of times a request failing with a transient error (network errors, HTTP 429 or 5xx) is retried. " +
+					"Non-idempotent requests are only retried when the API didn't process them. Defaults to `3`, `0` disables retries. " +
+					"May also be provided via the `CRUNCHLOOP_MAX_RETRIES` environment variable.",
+				Optional: true,
+			},
+			"mode": schema.StringAttribute{
+				MarkdownDescription: "Either `live` or `mock`. In `mock` mode the provider doesn't connect to a Crunchloop instance, it uses an embedded " +
+					"in-memory backend instead so modules can be tested with `terraform test`, `url` and `api_token` are ignored. Defaults to `live`. " +
+					"May also be provided via the `CRUNCHLOOP_MODE` environment variable.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(providerModeLive, providerModeMock),
+				},
+			},
+			"mock": schema.SingleNestedAttribute{
+				MarkdownDescription: "Configuration of the `mock` mode backend. Identifiers are assigned sequentially starting at `1`, " +
+					"seeded hosts first and then seeded vmis, in configuration order.",
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"state_file": schema.StringAttribute{
+						MarkdownDescription: "File where the backend keeps its state between Terraform commands, it's removed once every " +
+							"object created through the provider is destroyed and discarded when `hosts` or `vmis` change. A run that fails " +
+							"before destroying everything leaves it behind, remove it to start over with deterministic identifiers. " +
+							"Defaults to `" + defaultMockStateFile + "` in the working directory.",
+						Optional: true,
+					},
+					"hosts": schema.ListNestedAttribute{
+						MarkdownDescription: "Hosts available in the backend",
+						Optional:            true,
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: map[string]schema.Attribute{
+								"name": schema.StringAttribute{
+									MarkdownDescription: "Host name",
+									Required:            true,
+								},
+								"status": schema.StringAttribute{
+									MarkdownDescription: "Host status, either `online` or `offline`. Defaults to `online`",
+									Optional:            true,
+									Validators: []validator.String{
+										stringvalidator.OneOf(string(client.Online), string(client.Offline)),
+									},
+								},
+							},
+						},
+					},
+					"vmis": schema.ListNestedAttribute{
+						MarkdownDescription: "Vmis available in the backend",
+						Optional:            true,
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: map[string]schema.Attribute{
+								"name": schema.StringAttribute{
+									MarkdownDescription: "Vmi name",
+									Required:            true,
+								},
+							},
+						},
+					},
+				},
 			},
 		},
 	}
@@ -56,36 +195,256 @@ func (p *CrunchloopProvider) Configure(ctx context.Context, req provider.Configu
 		return
 	}
 
+	// If practitioner provided a configuration value for any of the
+	// attributes, it must be a known value.
+	if data.Url.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("url"),
+			"Unknown Crunchloop URL",
+			"The provider cannot create the Crunchloop API client as there is an unknown configuration value for the Crunchloop URL. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the CRUNCHLOOP_URL environment variable.",
+		)
+	}
+
+	if data.ApiToken.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("api_token"),
+			"Unknown Crunchloop API Token",
+			"The provider cannot create the Crunchloop API client as there is an unknown configuration value for the Crunchloop API token. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the CRUNCHLOOP_API_TOKEN environment variable.",
+		)
+	}
+
+	if data.DefaultTimeout.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("default_timeout"),
+			"Unknown Crunchloop Default Timeout",
+			"The provider cannot create the Crunchloop API client as there is an unknown configuration value for the default timeout. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the CRUNCHLOOP_DEFAULT_TIMEOUT environment variable.",
+		)
+	}
+
+	if data.MaxRetries.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_retries"),
+			"Unknown Crunchloop Max Retries",
+			"The provider cannot create the Crunchloop API client as there is an unknown configuration value for the max retries. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the CRUNCHLOOP_MAX_RETRIES environment variable.",
+		)
+	}
+
+	if data.Mode.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("mode"),
+			"Unknown Crunchloop Mode",
+			"The provider cannot create the Crunchloop API client as there is an unknown configuration value for the mode. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the CRUNCHLOOP_MODE environment variable.",
+		)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Default values to environment variables, but override
+	// with Terraform configuration value if set.
+	mode := os.Getenv("CRUNCHLOOP_MODE")
+	baseUrl := os.Getenv("CRUNCHLOOP_URL")
+	apiToken := os.Getenv("CRUNCHLOOP_API_TOKEN")
+	rawDefaultTimeout := os.Getenv("CRUNCHLOOP_DEFAULT_TIMEOUT")
+	rawMaxRetries := os.Getenv("CRUNCHLOOP_MAX_RETRIES")
+
+	if !data.Mode.IsNull() {
+		mode = data.Mode.ValueString()
+	}
+
+	if !data.Url.IsNull() {
+		baseUrl = data.Url.ValueString()
+	}
+
+	if !data.ApiToken.IsNull() {
+		apiToken = data.ApiToken.ValueString()
+	}
+
+	if !data.DefaultTimeout.IsNull() {
+		rawDefaultTimeout = data.DefaultTimeout.ValueString()
+	}
+
+	if !data.MaxRetries.IsNull() {
+		rawMaxRetries = strconv.FormatInt(data.MaxRetries.ValueInt64(), 10)
+	}
+
+	defaultTimeout := defaultOperationTimeout
+	if rawDefaultTimeout != "" {
+		parsed, err := time.ParseDuration(rawDefaultTimeout)
+		if err != nil || parsed <= 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("default_timeout"),
+				"Invalid Crunchloop Default Timeout",
+				fmt.Sprintf("The default timeout %q must be a positive duration such as \"10m\" or \"1h\".", rawDefaultTimeout),
+			)
+			return
+		}
+
+		defaultTimeout = parsed
+	}
+
+	maxRetries := defaultMaxRetries
+	if rawMaxRetries != "" {
+		parsed, err := strconv.Atoi(rawMaxRetries)
+		if err != nil || parsed < 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("max_retries"),
+				"Invalid Crunchloop Max Retries",
+				fmt.Sprintf("The max retries %q must be a non-negative integer.", rawMaxRetries),
+			)
+			return
+		}
+
+		maxRetries = parsed
+	}
+
+	var api services.Api
+
+	switch mode {
+	case "", providerModeLive:
+		api = p.liveClient(ctx, baseUrl, apiToken, maxRetries, &resp.Diagnostics)
+	case providerModeMock:
+		api = p.mockClient(ctx, data.Mock, &resp.Diagnostics)
+	default:
+		resp.Diagnostics.AddAttributeError(
+			path.Root("mode"),
+			"Invalid Crunchloop Mode",
+			fmt.Sprintf("The mode %q must be either %q or %q.", mode, providerModeLive, providerModeMock),
+		)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	providerData := &CrunchloopProviderData{
+		Client:         api,
+		DefaultTimeout: defaultTimeout,
+	}
+
+	resp.DataSourceData = providerData
+	resp.ResourceData = providerData
+}
+
+// liveClient returns a client for the Crunchloop instance at baseUrl.
+func (p *CrunchloopProvider) liveClient(ctx context.Context, baseUrl, apiToken string, maxRetries int, diags *diag.Diagnostics) services.Api {
+	if baseUrl == "" {
+		diags.AddAttributeError(
+			path.Root("url"),
+			"Missing Crunchloop URL",
+			"The provider cannot create the Crunchloop API client as there is a missing or empty value for the Crunchloop URL. "+
+				"Set the url value in the configuration or use the CRUNCHLOOP_URL environment variable.",
+		)
+		return nil
+	}
+
+	if err := validateUrl(baseUrl); err != nil {
+		diags.AddAttributeError(
+			path.Root("url"),
+			"Invalid Crunchloop URL",
+			fmt.Sprintf("The provider cannot create the Crunchloop API client as the Crunchloop URL %q is invalid: %s.", baseUrl, err),
+		)
+		return nil
+	}
+
 	// Example client configuration for data sources and resources
 	// client, err := client.NewClient(client.WithBaseURL(data.Url.ValueString()))
 	client, err := client.NewClientWithResponses(
-		data.Url.ValueString(),
+		baseUrl,
+		client.WithHTTPClient(transport.NewRetryDoer(transport.NewTracingDoer(transport.NewLoggingDoer(&http.Client{})), maxRetries)),
 		client.WithRequestEditorFn(
 			func(ctx context.Context, req *http.Request) error {
 				req.Header.Set("Accept", "application/json")
 
+				if apiToken != "" {
+					req.Header.Set("Authorization", "Bearer "+apiToken)
+				}
+
 				return nil
 			},
 		),
 	)
 
 	if err != nil {
-		tflog.Info(ctx, err.Error())
-		resp.Diagnostics.AddError(
-			"Configuration Error",
-			"Failed to create Crunchloop client",
+		diags.AddError(
+			"Unable to Create Crunchloop API Client",
+			"An unexpected error occurred when creating the Crunchloop API client. "+
+				"If the error is not clear, please contact the provider developers.\n\n"+
+				"Crunchloop Client Error: "+err.Error(),
 		)
-		return
+		return nil
+	}
+
+	tflog.Debug(ctx, "Configured Crunchloop client", map[string]interface{}{"url": baseUrl})
+
+	return client
+}
+
+// mockClient returns a client backed by the embedded in-memory backend,
+// seeded with the configured hosts and vmis.
+func (p *CrunchloopProvider) mockClient(ctx context.Context, config *mockModel, diags *diag.Diagnostics) services.Api {
+	if config == nil {
+		config = &mockModel{}
+	}
+
+	stateFile := defaultMockStateFile
+	if config.StateFile.ValueString() != "" {
+		stateFile = config.StateFile.ValueString()
+	}
+
+	options := mockapi.Options{}
+	for _, host := range config.Hosts {
+		status := client.Online
+		if !host.Status.IsNull() {
+			status = client.HostStatus(host.Status.ValueString())
+		}
+
+		options.Hosts = append(options.Hosts, mockapi.HostSeed{Name: host.Name.ValueString(), Status: status})
 	}
 
-	resp.DataSourceData = client
-	resp.ResourceData = client
+	for _, vmi := range config.Vmis {
+		options.Vmis = append(options.Vmis, vmi.Name.ValueString())
+	}
+
+	server, err := mockapi.Open(stateFile, options)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("mock").AtName("state_file"),
+			"Unable to Open Crunchloop Mock State",
+			fmt.Sprintf("The mock backend state %q can't be loaded, remove it to start over: %s", stateFile, err),
+		)
+		return nil
+	}
+
+	client, err := client.NewClientWithResponses(mockUrl, client.WithHTTPClient(transport.NewTracingDoer(transport.NewLoggingDoer(server))))
+	if err != nil {
+		diags.AddError(
+			"Unable to Create Crunchloop API Client",
+			"An unexpected error occurred when creating the Crunchloop mock client. "+
+				"If the error is not clear, please contact the provider developers.\n\n"+
+				"Crunchloop Client Error: "+err.Error(),
+		)
+		return nil
+	}
+
+	tflog.Debug(ctx, "Configured Crunchloop mock client", map[string]interface{}{"state_file": stateFile})
+
+	return client
 }
 
 func (p *CrunchloopProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewVmResource,
 		NewVmStateResource,
+		NewProxmoxHostResource,
+		NewProxmoxVmiResource,
+		NewVmActionResource,
 	}
 }
 
@@ -93,6 +452,10 @@ func (p *CrunchloopProvider) DataSources(ctx context.Context) []func() datasourc
 	return []func() datasource.DataSource{
 		NewHostDataSource,
 		NewVmiDataSource,
+		NewVmDataSource,
+		NewHostsDataSource,
+		NewVmisDataSource,
+		NewVmsDataSource,
 	}
 }
 
@@ -100,6 +463,24 @@ func (p *CrunchloopProvider) Functions(ctx context.Context) []func() function.Fu
 	return []func() function.Function{}
 }
 
+// validateUrl ensures the Crunchloop URL is an absolute http(s) URL.
+func validateUrl(rawUrl string) error {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return err
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("scheme must be http or https, got %q", u.Scheme)
+	}
+
+	if u.Host == "" {
+		return fmt.Errorf("missing host")
+	}
+
+	return nil
+}
+
 func New(version string) func() provider.Provider {
 	return func() provider.Provider {
 		return &CrunchloopProvider{
@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestValidateUrl(t *testing.T) {
+	cases := []struct {
+		name  string
+		url   string
+		valid bool
+	}{
+		{
+			name:  "https",
+			url:   "https://crunchloop.example.com",
+			valid: true,
+		},
+		{
+			name:  "http with port and path",
+			url:   "http://localhost:3000/crunchloop",
+			valid: true,
+		},
+		{
+			name: "unsupported scheme",
+			url:  "ftp://crunchloop.example.com",
+		},
+		{
+			name: "missing scheme",
+			url:  "crunchloop.example.com",
+		},
+		{
+			name: "missing host",
+			url:  "https://",
+		},
+		{
+			name: "unparsable",
+			url:  "https://crunchloop.example.com:port",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := validateUrl(c.url)
+			if c.valid && err != nil {
+				t.Errorf("expected %q to be valid, got: %s", c.url, err)
+			}
+
+			if !c.valid && err == nil {
+				t.Errorf("expected %q to be invalid", c.url)
+			}
+		})
+	}
+}
@@ -0,0 +1,271 @@
+package provider_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/acctest"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccProvider_mockMode(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "crunchloop-mock-state.json")
+
+	config := fmt.Sprintf(`
+provider "crunchloop" {
+  mode = "mock"
+
+  mock = {
+    state_file = %q
+
+    hosts = [
+      { name = "host-1" },
+      { name = "host-2", status = "offline" },
+    ]
+
+    vmis = [
+      { name = "ubuntu-jammy" },
+    ]
+  }
+}
+
+data "crunchloop_host" "online" {
+  name           = "host-1"
+  require_online = true
+}
+
+data "crunchloop_host" "offline" {
+  name = "host-2"
+}
+
+data "crunchloop_vmi" "jammy" {
+  name = "ubuntu-jammy"
+}
+
+resource "crunchloop_vm" "test" {
+  name                       = "test"
+  vmi_id                     = data.crunchloop_vmi.jammy.id
+  host_id                    = data.crunchloop_host.online.id
+  cores                      = 1
+  memory_megabytes           = 512
+  root_volume_size_gigabytes = 10
+}
+`, stateFile)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		// Once every object created through the provider is destroyed the
+		// state file is removed, the next run starts over.
+		CheckDestroy: func(*terraform.State) error {
+			if _, err := os.Stat(stateFile); !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("expected the mock state file to be removed, got %v", err)
+			}
+
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					// Seeds get the first identifiers, in configuration order.
+					resource.TestCheckResourceAttr("data.crunchloop_host.online", "id", "1"),
+					resource.TestCheckResourceAttr("data.crunchloop_host.offline", "id", "2"),
+					resource.TestCheckResourceAttr("data.crunchloop_host.offline", "status", "offline"),
+					resource.TestCheckResourceAttr("data.crunchloop_vmi.jammy", "id", "3"),
+					resource.TestCheckResourceAttr("crunchloop_vm.test", "id", "4"),
+					resource.TestCheckResourceAttr("crunchloop_vm.test", "host_id", "1"),
+					resource.TestCheckResourceAttr("crunchloop_vm.test", "status", "running"),
+					func(*terraform.State) error {
+						if _, err := os.Stat(stateFile); err != nil {
+							return fmt.Errorf("expected the mock state file to be saved: %w", err)
+						}
+
+						return nil
+					},
+				),
+			},
+			// The vm is found again by the next Terraform command, i.e. by a
+			// newly configured provider, so the plan is empty.
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+			{
+				ResourceName:      "crunchloop_vm.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				Config:            config,
+			},
+		},
+	})
+}
+
+func TestAccProvider_apiToken(t *testing.T) {
+	t.Setenv("CRUNCHLOOP_API_TOKEN", "")
+
+	server, _ := acctest.NewServer(t)
+	server.AddHost("host-1", client.Online)
+
+	url := testAccTokenServer(t, server, "secret")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccProviderApiTokenConfig(url, ""),
+				ExpectError: regexp.MustCompile(`Authentication Error \(HTTP 401 Unauthorized\)`),
+			},
+			{
+				Config:      testAccProviderApiTokenConfig(url, "wrong"),
+				ExpectError: regexp.MustCompile(`Authentication Error \(HTTP 403 Forbidden\)`),
+			},
+			// The last step leaves a valid configuration for the destroy run.
+			{
+				Config: testAccProviderApiTokenConfig(url, "secret"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.crunchloop_host.test", "name", "host-1"),
+				),
+			},
+		},
+	})
+}
+
+func testAccProviderApiTokenConfig(url, apiToken string) string {
+	return fmt.Sprintf(`
+provider "crunchloop" {
+  url       = %q
+  api_token = %q
+}
+
+data "crunchloop_host" "test" {
+  name = "host-1"
+}
+`, url, apiToken)
+}
+
+func TestAccProvider_configure(t *testing.T) {
+	server, _ := acctest.NewServer(t)
+	server.AddHost("host-1", client.Online)
+	url := testAccTokenServer(t, server, "secret")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				PreConfig:   testAccProviderEnv(t, nil),
+				Config:      testAccProviderHostConfig(""),
+				ExpectError: regexp.MustCompile("Missing Crunchloop URL"),
+			},
+			{
+				PreConfig:   testAccProviderEnv(t, nil),
+				Config:      testAccProviderHostConfig(`url = "ftp://crunchloop.example.com"`),
+				ExpectError: regexp.MustCompile(`Invalid Crunchloop URL`),
+			},
+			{
+				PreConfig:   testAccProviderEnv(t, nil),
+				Config:      testAccProviderHostConfig(`url = "https://"`),
+				ExpectError: regexp.MustCompile(`Invalid Crunchloop URL`),
+			},
+			{
+				PreConfig: testAccProviderEnv(t, nil),
+				Config: `
+resource "terraform_data" "url" {
+  input = "https://crunchloop.example.com"
+}
+` + testAccProviderHostConfig("url = terraform_data.url.output"),
+				ExpectError: regexp.MustCompile("Unknown Crunchloop URL"),
+			},
+			{
+				PreConfig:   testAccProviderEnv(t, map[string]string{"CRUNCHLOOP_URL": url, "CRUNCHLOOP_DEFAULT_TIMEOUT": "soon"}),
+				Config:      testAccProviderHostConfig(""),
+				ExpectError: regexp.MustCompile("Invalid Crunchloop Default Timeout"),
+			},
+			{
+				PreConfig:   testAccProviderEnv(t, map[string]string{"CRUNCHLOOP_URL": url, "CRUNCHLOOP_MAX_RETRIES": "-1"}),
+				Config:      testAccProviderHostConfig(""),
+				ExpectError: regexp.MustCompile("Invalid Crunchloop Max Retries"),
+			},
+			{
+				PreConfig:   testAccProviderEnv(t, map[string]string{"CRUNCHLOOP_URL": url, "CRUNCHLOOP_MODE": "replay"}),
+				Config:      testAccProviderHostConfig(""),
+				ExpectError: regexp.MustCompile("Invalid Crunchloop Mode"),
+			},
+			// The configuration is taken from the environment when the
+			// provider block doesn't set it. The last step leaves a valid
+			// configuration for the destroy run.
+			{
+				PreConfig: testAccProviderEnv(t, map[string]string{
+					"CRUNCHLOOP_URL":             url,
+					"CRUNCHLOOP_API_TOKEN":       "secret",
+					"CRUNCHLOOP_DEFAULT_TIMEOUT": "10m",
+					"CRUNCHLOOP_MAX_RETRIES":     "0",
+					"CRUNCHLOOP_MODE":            "live",
+				}),
+				Config: testAccProviderHostConfig(""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.crunchloop_host.test", "name", "host-1"),
+				),
+			},
+		},
+	})
+}
+
+// testAccProviderEnvNames are the environment variables read by the provider.
+var testAccProviderEnvNames = []string{
+	"CRUNCHLOOP_URL",
+	"CRUNCHLOOP_API_TOKEN",
+	"CRUNCHLOOP_DEFAULT_TIMEOUT",
+	"CRUNCHLOOP_MAX_RETRIES",
+	"CRUNCHLOOP_MODE",
+}
+
+// testAccProviderEnv returns a PreConfig function setting the provider
+// environment variables to the given values, unset ones are cleared.
+func testAccProviderEnv(t *testing.T, values map[string]string) func() {
+	return func() {
+		for _, name := range testAccProviderEnvNames {
+			t.Setenv(name, values[name])
+		}
+	}
+}
+
+// testAccTokenServer serves the API behind a check of the Authorization
+// header and returns its url. Requests without a token are rejected with a
+// 401 and requests with a different token with a 403.
+func testAccTokenServer(t *testing.T, handler http.Handler, token string) string {
+	t.Helper()
+
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Header.Get("Authorization") {
+		case "Bearer " + token:
+			handler.ServeHTTP(w, r)
+		case "":
+			http.Error(w, "missing api token", http.StatusUnauthorized)
+		default:
+			http.Error(w, "invalid api token", http.StatusForbidden)
+		}
+	}))
+	t.Cleanup(httpServer.Close)
+
+	return httpServer.URL
+}
+
+func testAccProviderHostConfig(providerAttributes string) string {
+	return fmt.Sprintf(`
+provider "crunchloop" {
+  %s
+}
+
+data "crunchloop_host" "test" {
+  name = "host-1"
+}
+`, providerAttributes)
+}
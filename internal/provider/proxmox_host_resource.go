@@ -0,0 +1,248 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/services"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/tracing"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &ProxmoxHostResource{}
+var _ resource.ResourceWithImportState = &ProxmoxHostResource{}
+
+// hostRequestAttributes maps the fields of the create request to the
+// attributes they're built from, so input errors point at them.
+var hostRequestAttributes = map[string]path.Path{
+	"name":         path.Root("name"),
+	"ip_address":   path.Root("ip_address"),
+	"ssh_username": path.Root("ssh_username"),
+	"ssh_password": path.Root("ssh_password"),
+}
+
+func NewProxmoxHostResource() resource.Resource {
+	return &ProxmoxHostResource{}
+}
+
+// ProxmoxHostResource defines the resource implementation.
+type ProxmoxHostResource struct {
+	service *services.HostService
+}
+
+// ProxmoxHostResourceModel describes the resource data model.
+type ProxmoxHostResourceModel struct {
+	Id          types.String `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	IpAddress   types.String `tfsdk:"ip_address"`
+	SshUsername types.String `tfsdk:"ssh_username"`
+	SshPassword types.String `tfsdk:"ssh_password"`
+	Status      types.String `tfsdk:"status"`
+}
+
+func (r *ProxmoxHostResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_proxmox_host"
+}
+
+func (r *ProxmoxHostResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Proxmox host resource. The API can't deregister hosts, destroying the resource only removes it from state",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identifier",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Name of the Host",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"ip_address": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "IP address used to reach the Proxmox host. Not returned by the API, an imported host adopts the configured value without being replaced",
+				PlanModifiers: []planmodifier.String{
+					requiresReplaceUnlessImported(),
+				},
+			},
+			"ssh_username": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Ssh username used to manage the Proxmox host. Not returned by the API, an imported host adopts the configured value without being replaced",
+				PlanModifiers: []planmodifier.String{
+					requiresReplaceUnlessImported(),
+				},
+			},
+			"ssh_password": schema.StringAttribute{
+				Required:            true,
+				Sensitive:           true,
+				MarkdownDescription: "Ssh password used to manage the Proxmox host. Not returned by the API, an imported host adopts the configured value without being replaced",
+				PlanModifiers: []planmodifier.String{
+					requiresReplaceUnlessImported(),
+				},
+			},
+			"status": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Host status (`online` or `offline`)",
+			},
+		},
+	}
+}
+
+func (r *ProxmoxHostResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*CrunchloopProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *CrunchloopProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.service = services.NewHostService(providerData.Client)
+}
+
+func (r *ProxmoxHostResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := tracing.Start(ctx, "crunchloop_proxmox_host", "Create")
+	defer tracing.End(span, &resp.Diagnostics)
+
+	var data ProxmoxHostResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createOptions := client.CreateProxmoxHostJSONRequestBody{
+		Name:        data.Name.ValueString(),
+		IpAddress:   data.IpAddress.ValueString(),
+		SshUsername: data.SshUsername.ValueString(),
+		SshPassword: data.SshPassword.ValueString(),
+	}
+
+	host, err := r.service.CreateProxmoxHost(ctx, createOptions)
+	if err != nil {
+		addApiErrorWithAttributes(&resp.Diagnostics, err, hostRequestAttributes)
+		return
+	}
+
+	data.hostModelToStateResource(host)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ProxmoxHostResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := tracing.Start(ctx, "crunchloop_proxmox_host", "Read")
+	defer tracing.End(span, &resp.Diagnostics)
+
+	var data ProxmoxHostResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id, _ := strconv.Atoi(data.Id.ValueString())
+	host, err := r.service.GetHost(ctx, int32(id))
+	if err != nil {
+		if services.IsNotFound(err) {
+			tflog.Warn(ctx, fmt.Sprintf("Host %d not found, removing it from state", id))
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		addApiError(&resp.Diagnostics, err)
+		return
+	}
+
+	data.hostModelToStateResource(host)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ProxmoxHostResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := tracing.Start(ctx, "crunchloop_proxmox_host", "Update")
+	defer tracing.End(span, &resp.Diagnostics)
+
+	var data ProxmoxHostResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Hosts can't be updated through the API, configurable attributes only
+	// change in place right after an import, when the state doesn't know them
+	// yet, so we only need to persist the planned values along with the
+	// current status.
+	id, _ := strconv.Atoi(data.Id.ValueString())
+	host, err := r.service.GetHost(ctx, int32(id))
+	if err != nil {
+		addApiError(&resp.Diagnostics, err)
+		return
+	}
+
+	data.hostModelToStateResource(host)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ProxmoxHostResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := tracing.Start(ctx, "crunchloop_proxmox_host", "Delete")
+	defer tracing.End(span, &resp.Diagnostics)
+
+	var data ProxmoxHostResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The API has no endpoint to deregister a host, deleting the resource
+	// only removes it from state.
+	resp.Diagnostics.AddWarning(
+		"Host Not Deregistered",
+		fmt.Sprintf("Host %s was removed from state but is still registered in Crunchloop, the API doesn't support deregistering hosts.", data.Id.ValueString()),
+	)
+}
+
+func (r *ProxmoxHostResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// requiresReplaceUnlessImported requires a replacement when an attribute the
+// API doesn't return changes. Imported hosts don't have it in state, the
+// configured value is adopted instead of replacing the host.
+func requiresReplaceUnlessImported() planmodifier.String {
+	return stringplanmodifier.RequiresReplaceIf(
+		func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+			resp.RequiresReplace = !req.StateValue.IsNull()
+		},
+		"Changing this value requires a replacement, unless the resource was imported and the value isn't known yet.",
+		"Changing this value requires a replacement, unless the resource was imported and the value isn't known yet.",
+	)
+}
+
+func (d *ProxmoxHostResourceModel) hostModelToStateResource(host *client.Host) {
+	d.Id = idPointerValue(host.Id)
+	d.Name = types.StringPointerValue(host.Name)
+	d.Status = types.StringPointerValue((*string)(host.Status))
+}
@@ -0,0 +1,93 @@
+package provider_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/acctest"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+)
+
+func TestAccProxmoxHostResource(t *testing.T) {
+	_, url := acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: acctest.ProviderConfig(url) + testAccProxmoxHostResourceConfig("10.0.0.10"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("crunchloop_proxmox_host.test", "id", "1"),
+					resource.TestCheckResourceAttr("crunchloop_proxmox_host.test", "name", "pve-1"),
+					resource.TestCheckResourceAttr("crunchloop_proxmox_host.test", "status", "online"),
+				),
+			},
+			// ImportState testing, the API doesn't return the connection
+			// details.
+			{
+				ResourceName:            "crunchloop_proxmox_host.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"ip_address", "ssh_username", "ssh_password"},
+			},
+			// Changing the connection details replaces the host
+			{
+				Config: acctest.ProviderConfig(url) + testAccProxmoxHostResourceConfig("10.0.0.11"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("crunchloop_proxmox_host.test", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestAccProxmoxHostResource_import(t *testing.T) {
+	server, url := acctest.NewServer(t)
+	host := server.AddHost("pve-1", client.Online)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:             acctest.ProviderConfig(url) + testAccProxmoxHostResourceConfig("10.0.0.10"),
+				ResourceName:       "crunchloop_proxmox_host.test",
+				ImportState:        true,
+				ImportStateId:      fmt.Sprint(*host.Id),
+				ImportStatePersist: true,
+			},
+			// The imported host adopts the configured connection details
+			// instead of being replaced.
+			{
+				Config: acctest.ProviderConfig(url) + testAccProxmoxHostResourceConfig("10.0.0.10"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("crunchloop_proxmox_host.test", plancheck.ResourceActionUpdate),
+					},
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("crunchloop_proxmox_host.test", "id", fmt.Sprint(*host.Id)),
+					resource.TestCheckResourceAttr("crunchloop_proxmox_host.test", "ip_address", "10.0.0.10"),
+				),
+			},
+		},
+	})
+}
+
+func testAccProxmoxHostResourceConfig(ipAddress string) string {
+	return fmt.Sprintf(`
+resource "crunchloop_proxmox_host" "test" {
+  name         = "pve-1"
+  ip_address   = %q
+  ssh_username = "root"
+  ssh_password = "secret"
+}
+`, ipAddress)
+}
@@ -0,0 +1,263 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/services"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/tracing"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &ProxmoxVmiResource{}
+var _ resource.ResourceWithImportState = &ProxmoxVmiResource{}
+
+// vmiRequestAttributes maps the fields of the create request to the
+// attributes they're built from, so input errors point at them.
+var vmiRequestAttributes = map[string]path.Path{
+	"name":   path.Root("name"),
+	"url":    path.Root("url"),
+	"sha256": path.Root("sha256"),
+}
+
+// defaultVmiCreateTimeout bounds the image download when no create timeout is
+// configured. Cloud images are usually several gigabytes so the provider
+// default_timeout, meant for vm operations, doesn't apply.
+const defaultVmiCreateTimeout = 30 * time.Minute
+
+func NewProxmoxVmiResource() resource.Resource {
+	return &ProxmoxVmiResource{}
+}
+
+// ProxmoxVmiResource defines the resource implementation.
+type ProxmoxVmiResource struct {
+	service *services.VmiService
+}
+
+// ProxmoxVmiResourceModel describes the resource data model.
+type ProxmoxVmiResourceModel struct {
+	Id       types.String   `tfsdk:"id"`
+	Name     types.String   `tfsdk:"name"`
+	Url      types.String   `tfsdk:"url"`
+	Sha256   types.String   `tfsdk:"sha256"`
+	Status   types.String   `tfsdk:"status"`
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *ProxmoxVmiResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_proxmox_vmi"
+}
+
+func (r *ProxmoxVmiResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Proxmox Vmi resource. The API can't delete vmis, destroying the resource only removes it from state",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identifier",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Name of the Vmi",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"url": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Url of the cloud image to download",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"sha256": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Sha256 checksum used to verify the downloaded cloud image",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"status": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Vmi status",
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+			}),
+		},
+	}
+}
+
+func (r *ProxmoxVmiResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*CrunchloopProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *CrunchloopProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.service = services.NewVmiService(providerData.Client)
+}
+
+func (r *ProxmoxVmiResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := tracing.Start(ctx, "crunchloop_proxmox_vmi", "Create")
+	defer tracing.End(span, &resp.Diagnostics)
+
+	var data ProxmoxVmiResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The image is downloaded by the host, large images may need a longer
+	// create timeout.
+	createTimeout, diags := data.Timeouts.Create(ctx, defaultVmiCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	createOptions := client.CreateProxmoxVmiJSONRequestBody{
+		Name:   data.Name.ValueString(),
+		Url:    data.Url.ValueString(),
+		Sha256: data.Sha256.ValueString(),
+	}
+
+	vmi, err := r.service.CreateProxmoxVmi(ctx, createOptions)
+	if err != nil {
+		// The vmi may have been registered, keep it in state so it's tainted
+		// instead of leaked
+		if vmi != nil {
+			data.vmiModelToStateResource(vmi)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		}
+
+		addApiErrorWithAttributes(&resp.Diagnostics, err, vmiRequestAttributes)
+		return
+	}
+
+	data.vmiModelToStateResource(vmi)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ProxmoxVmiResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := tracing.Start(ctx, "crunchloop_proxmox_vmi", "Read")
+	defer tracing.End(span, &resp.Diagnostics)
+
+	var data ProxmoxVmiResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id, _ := strconv.Atoi(data.Id.ValueString())
+	vmi, err := r.service.GetVmi(ctx, int32(id))
+	if err != nil {
+		if services.IsNotFound(err) {
+			tflog.Warn(ctx, fmt.Sprintf("Vmi %d not found, removing it from state", id))
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		addApiError(&resp.Diagnostics, err)
+		return
+	}
+
+	data.vmiModelToStateResource(vmi)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ProxmoxVmiResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := tracing.Start(ctx, "crunchloop_proxmox_vmi", "Update")
+	defer tracing.End(span, &resp.Diagnostics)
+
+	var data ProxmoxVmiResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Vmis can't be updated through the API, every configurable attribute
+	// requires a replacement so only the timeouts can change, we only need
+	// to persist them along with the current status.
+	id, _ := strconv.Atoi(data.Id.ValueString())
+	vmi, err := r.service.GetVmi(ctx, int32(id))
+	if err != nil {
+		addApiError(&resp.Diagnostics, err)
+		return
+	}
+
+	data.vmiModelToStateResource(vmi)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ProxmoxVmiResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := tracing.Start(ctx, "crunchloop_proxmox_vmi", "Delete")
+	defer tracing.End(span, &resp.Diagnostics)
+
+	var data ProxmoxVmiResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The API has no endpoint to delete a vmi, deleting the resource only
+	// removes it from state.
+	resp.Diagnostics.AddWarning(
+		"Vmi Not Deleted",
+		fmt.Sprintf("Vmi %s was removed from state but still exists in Crunchloop, the API doesn't support deleting vmis.", data.Id.ValueString()),
+	)
+}
+
+func (r *ProxmoxVmiResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (d *ProxmoxVmiResourceModel) vmiModelToStateResource(vmi *client.VirtualMachineImage) {
+	d.Id = idPointerValue(vmi.Id)
+	d.Name = types.StringPointerValue(vmi.Name)
+	d.Status = types.StringPointerValue((*string)(vmi.Status))
+
+	if vmi.Url != nil {
+		d.Url = types.StringValue(*vmi.Url)
+	}
+
+	if vmi.Sha256 != nil {
+		d.Sha256 = types.StringValue(*vmi.Sha256)
+	}
+}
@@ -0,0 +1,88 @@
+package provider_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+)
+
+func TestAccProxmoxVmiResource(t *testing.T) {
+	_, url := acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: acctest.ProviderConfig(url) + testAccProxmoxVmiResourceConfig("10m"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("crunchloop_proxmox_vmi.test", "id", "1"),
+					resource.TestCheckResourceAttr("crunchloop_proxmox_vmi.test", "status", "available"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "crunchloop_proxmox_vmi.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"timeouts"},
+			},
+			// Changing the timeouts only updates the state
+			{
+				Config: acctest.ProviderConfig(url) + testAccProxmoxVmiResourceConfig("20m"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("crunchloop_proxmox_vmi.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("crunchloop_proxmox_vmi.test", "id", "1"),
+					resource.TestCheckResourceAttr("crunchloop_proxmox_vmi.test", "status", "available"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccProxmoxVmiResource_createTimeout(t *testing.T) {
+	_, url := acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// The vmi is kept in state when it isn't available in time, so
+			// it's not leaked.
+			{
+				Config:      acctest.ProviderConfig(url) + testAccProxmoxVmiResourceConfig("1s"),
+				ExpectError: regexp.MustCompile("timeout waiting for vmi 1"),
+			},
+			{
+				Config: acctest.ProviderConfig(url) + testAccProxmoxVmiResourceConfig("10m"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("crunchloop_proxmox_vmi.test", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+				Check: resource.TestCheckResourceAttr("crunchloop_proxmox_vmi.test", "id", "2"),
+			},
+		},
+	})
+}
+
+func testAccProxmoxVmiResourceConfig(createTimeout string) string {
+	return fmt.Sprintf(`
+resource "crunchloop_proxmox_vmi" "test" {
+  name   = "ubuntu-jammy"
+  url    = "https://cloud-images.ubuntu.com/jammy/20241002/jammy-server-cloudimg-amd64.img"
+  sha256 = "55c687a9a242fab7b0ec89ac69f9def77696c4e160e6f640879a0b0031a08318"
+
+  timeouts {
+    create = %q
+  }
+}
+`, createTimeout)
+}
@@ -0,0 +1,93 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"golang.org/x/crypto/ssh"
+)
+
+var _ validator.String = base64Validator{}
+
+// base64Validator validates that a string is standard base64 encoded.
+type base64Validator struct{}
+
+func (v base64Validator) Description(ctx context.Context) string {
+	return "value must be base64 encoded"
+}
+
+func (v base64Validator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v base64Validator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := base64.StdEncoding.DecodeString(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Base64 Value",
+			fmt.Sprintf("Attribute %s %s, got error: %s. Use the base64encode function to encode it.", req.Path, v.Description(ctx), err),
+		)
+	}
+}
+
+var _ validator.String = sshPublicKeyValidator{}
+
+// sshPublicKeyValidator validates that a string is an OpenSSH public key in
+// the authorized_keys format, e.g. "ssh-ed25519 AAAA... user@host".
+type sshPublicKeyValidator struct{}
+
+func (v sshPublicKeyValidator) Description(ctx context.Context) string {
+	return "value must be an OpenSSH public key"
+}
+
+func (v sshPublicKeyValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v sshPublicKeyValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, _, _, _, err := ssh.ParseAuthorizedKey([]byte(req.ConfigValue.ValueString())); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid SSH Public Key",
+			fmt.Sprintf("Attribute %s %s, got error: %s.", req.Path, v.Description(ctx), err),
+		)
+	}
+}
+
+var _ validator.String = regexpValidator{}
+
+// regexpValidator validates that a string is a valid regular expression.
+type regexpValidator struct{}
+
+func (v regexpValidator) Description(ctx context.Context) string {
+	return "value must be a valid regular expression"
+}
+
+func (v regexpValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v regexpValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := regexp.Compile(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Regular Expression",
+			fmt.Sprintf("Attribute %s %s, got error: %s.", req.Path, v.Description(ctx), err),
+		)
+	}
+}
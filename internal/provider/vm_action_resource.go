@@ -0,0 +1,221 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/services"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/tracing"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	vmActionStart  = "start"
+	vmActionStop   = "stop"
+	vmActionReboot = "reboot"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &VmActionResource{}
+
+func NewVmActionResource() resource.Resource {
+	return &VmActionResource{}
+}
+
+// VmActionResource defines the resource implementation.
+//
+// Unlike crunchloop_vm_state, it doesn't track the vm status: the action is
+// performed once when the resource is created (or replaced because its
+// triggers changed) and the vm is left alone afterwards.
+type VmActionResource struct {
+	service        *services.VmService
+	defaultTimeout time.Duration
+}
+
+// VmActionResourceModel describes the resource data model.
+type VmActionResourceModel struct {
+	VmId     types.String   `tfsdk:"vm_id"`
+	Action   types.String   `tfsdk:"action"`
+	Triggers types.Map      `tfsdk:"triggers"`
+	Status   types.String   `tfsdk:"status"`
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *VmActionResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vm_action"
+}
+
+func (r *VmActionResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Performs a lifecycle action (`start`, `stop` or `reboot`) on a Vm once, when the resource is created or its " +
+			"`triggers` change. The Vm status isn't tracked afterwards, use `crunchloop_vm_state` to keep a Vm in a desired status.",
+
+		Attributes: map[string]schema.Attribute{
+			"vm_id": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Vm identifier",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"action": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Action to perform, one of `start`, `stop` or `reboot`",
+				Validators: []validator.String{
+					stringvalidator.OneOf(vmActionStart, vmActionStop, vmActionReboot),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"triggers": schema.MapAttribute{
+				ElementType:         types.StringType,
+				Optional:            true,
+				MarkdownDescription: "Arbitrary map of values that, when changed, performs the action again",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"status": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Vm status once the action completed",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+			}),
+		},
+	}
+}
+
+func (r *VmActionResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*CrunchloopProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *CrunchloopProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.service = services.NewVmService(providerData.Client)
+	r.defaultTimeout = providerData.DefaultTimeout
+}
+
+func (r *VmActionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := tracing.Start(ctx, "crunchloop_vm_action", "Create")
+	defer tracing.End(span, &resp.Diagnostics)
+
+	var data VmActionResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := data.Timeouts.Create(ctx, r.defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	vm, err := r.performAction(ctx, &data)
+	if err != nil {
+		addApiError(&resp.Diagnostics, err)
+		return
+	}
+
+	data.Status = types.StringValue(string(*vm.Status))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *VmActionResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// The action already happened, the vm status is intentionally not
+	// refreshed so other tooling is free to change it.
+}
+
+func (r *VmActionResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := tracing.Start(ctx, "crunchloop_vm_action", "Update")
+	defer tracing.End(span, &resp.Diagnostics)
+
+	var data VmActionResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Every attribute that affects the action requires a replacement, only
+	// timeouts can be updated in place.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *VmActionResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := tracing.Start(ctx, "crunchloop_vm_action", "Delete")
+	defer tracing.End(span, &resp.Diagnostics)
+
+	var data VmActionResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	tflog.Debug(ctx, fmt.Sprintf("Deleting a crunchloop_vm_action resource doesn't change the vm: %s", data.VmId.String()))
+}
+
+func (r *VmActionResource) performAction(ctx context.Context, data *VmActionResourceModel) (*client.VirtualMachine, error) {
+	id, _ := strconv.Atoi(data.VmId.ValueString())
+
+	switch data.Action.ValueString() {
+	case vmActionReboot:
+		return r.service.RebootVm(ctx, int32(id))
+	case vmActionStart, vmActionStop:
+		vm, err := r.service.GetVm(ctx, int32(id))
+		if err != nil {
+			return nil, err
+		}
+
+		if data.Action.ValueString() == vmActionStart {
+			if *vm.Status == client.VirtualMachineStatusRunning {
+				tflog.Debug(ctx, fmt.Sprintf("Vm %d is already running", id))
+				return vm, nil
+			}
+
+			return r.service.StartVm(ctx, int32(id))
+		}
+
+		if *vm.Status == client.VirtualMachineStatusStopped {
+			tflog.Debug(ctx, fmt.Sprintf("Vm %d is already stopped", id))
+			return vm, nil
+		}
+
+		return r.service.StopVm(ctx, int32(id))
+	}
+
+	return nil, fmt.Errorf("unsupported vm action %q", data.Action.ValueString())
+}
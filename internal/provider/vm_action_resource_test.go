@@ -0,0 +1,151 @@
+package provider_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/acctest"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/mockapi"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccVmActionResource(t *testing.T) {
+	server, url := acctest.NewServer(t)
+	server.AddHost("host-1", client.Online)
+	vmi := server.AddVmi("ubuntu-jammy")
+
+	config := func(action string, revision string) string {
+		return acctest.ProviderConfig(url) + testAccVmActionResourceConfig(*vmi.Id, action, revision)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: config("stop", "1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("crunchloop_vm_action.test", "vm_id", "crunchloop_vm.test", "id"),
+					resource.TestCheckResourceAttr("crunchloop_vm_action.test", "action", "stop"),
+					resource.TestCheckResourceAttr("crunchloop_vm_action.test", "status", "stopped"),
+					testAccCheckVmStatus(server, "crunchloop_vm.test", client.VirtualMachineStatusStopped),
+					testAccCheckRequestCount(server, "POST /api/v1/vms/3/stop", 1),
+				),
+			},
+			// Changing the action replaces the resource and performs it.
+			{
+				Config: config("start", "1"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("crunchloop_vm_action.test", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("crunchloop_vm_action.test", "status", "running"),
+					testAccCheckVmStatus(server, "crunchloop_vm.test", client.VirtualMachineStatusRunning),
+					testAccCheckRequestCount(server, "POST /api/v1/vms/3/start", 1),
+				),
+			},
+			// Changing the triggers performs the action again, it's a no-op
+			// when the vm is already in the target status.
+			{
+				Config: config("start", "2"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("crunchloop_vm_action.test", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("crunchloop_vm_action.test", "status", "running"),
+					testAccCheckRequestCount(server, "POST /api/v1/vms/3/start", 1),
+				),
+			},
+			// The vm status isn't tracked, changing it outside of Terraform
+			// doesn't perform the action again.
+			{
+				PreConfig: func() {
+					if !server.SetVmStatus(3, client.VirtualMachineStatusStopped) {
+						t.Fatal("vm 3 not found")
+					}
+				},
+				Config: config("start", "2"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckVmStatus(server, "crunchloop_vm.test", client.VirtualMachineStatusStopped),
+					testAccCheckRequestCount(server, "POST /api/v1/vms/3/start", 1),
+				),
+			},
+			{
+				Config: config("start", "3"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckVmStatus(server, "crunchloop_vm.test", client.VirtualMachineStatusRunning),
+					testAccCheckRequestCount(server, "POST /api/v1/vms/3/start", 2),
+				),
+			},
+			{
+				Config: config("reboot", "3"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("crunchloop_vm_action.test", "status", "running"),
+					testAccCheckVmStatus(server, "crunchloop_vm.test", client.VirtualMachineStatusRunning),
+					testAccCheckRequestCount(server, "POST /api/v1/vms/3/reboot", 1),
+				),
+			},
+			{
+				Config: config("reboot", "4"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("crunchloop_vm_action.test", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+				Check: testAccCheckRequestCount(server, "POST /api/v1/vms/3/reboot", 2),
+			},
+		},
+	})
+}
+
+// testAccCheckRequestCount checks the number of times the API received the
+// given request, e.g. "POST /api/v1/vms/3/start".
+func testAccCheckRequestCount(server *mockapi.Server, request string, expected int) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		var count int
+		for _, r := range server.Requests() {
+			if r == request {
+				count++
+			}
+		}
+
+		if count != expected {
+			return fmt.Errorf("expected %d %q requests, got %d", expected, request, count)
+		}
+
+		return nil
+	}
+}
+
+func testAccVmActionResourceConfig(vmiId int32, action string, revision string) string {
+	return fmt.Sprintf(`
+resource "crunchloop_vm" "test" {
+  name                       = "test"
+  vmi_id                     = %d
+  cores                      = 1
+  memory_megabytes           = 512
+  root_volume_size_gigabytes = 10
+}
+
+resource "crunchloop_vm_action" "test" {
+  vm_id  = crunchloop_vm.test.id
+  action = %q
+
+  triggers = {
+    revision = %q
+  }
+}
+`, vmiId, action, revision)
+}
@@ -0,0 +1,332 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/services"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/tracing"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &VmDataSource{}
+var _ datasource.DataSourceWithConfigValidators = &VmDataSource{}
+
+func NewVmDataSource() datasource.DataSource {
+	return &VmDataSource{}
+}
+
+// VmDataSource defines the data source implementation.
+type VmDataSource struct {
+	service *services.VmService
+}
+
+// VmDataSourceModel describes the data source data model.
+type VmDataSourceModel struct {
+	Id              types.String   `tfsdk:"id"`
+	Name            types.String   `tfsdk:"name"`
+	Status          types.String   `tfsdk:"status"`
+	Cores           types.Int32    `tfsdk:"cores"`
+	MemoryMegabytes types.Int32    `tfsdk:"memory_megabytes"`
+	Host            *vmHostModel   `tfsdk:"host"`
+	Vmi             *vmVmiModel    `tfsdk:"vmi"`
+	RootVolume      *vmVolumeModel `tfsdk:"root_volume"`
+	Nic             *vmNicModel    `tfsdk:"nic"`
+}
+
+type vmVmiModel struct {
+	Id   types.String `tfsdk:"id"`
+	Name types.String `tfsdk:"name"`
+}
+
+type vmVolumeModel struct {
+	Id        types.String `tfsdk:"id"`
+	Name      types.String `tfsdk:"name"`
+	SizeBytes types.Int64  `tfsdk:"size_bytes"`
+	Status    types.String `tfsdk:"status"`
+}
+
+type vmNicModel struct {
+	Id        types.String `tfsdk:"id"`
+	IpAddress types.String `tfsdk:"ip_address"`
+	Dhcp      types.Bool   `tfsdk:"dhcp"`
+}
+
+func (d *VmDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vm"
+}
+
+func (d *VmDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	attributes := vmDataSourceAttributes()
+	attributes["id"] = schema.StringAttribute{
+		MarkdownDescription: "Vm identifier, conflicts with `name`",
+		Optional:            true,
+		Computed:            true,
+	}
+	attributes["name"] = schema.StringAttribute{
+		MarkdownDescription: "Vm name, it must be unique. Conflicts with `id`",
+		Optional:            true,
+		Computed:            true,
+	}
+
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Vm data source, looks up an existing Vm by `id` or `name`",
+
+		Attributes: attributes,
+	}
+}
+
+func (d *VmDataSource) ConfigValidators(ctx context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(
+			path.MatchRoot("id"),
+			path.MatchRoot("name"),
+		),
+	}
+}
+
+func (d *VmDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*CrunchloopProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *CrunchloopProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.service = services.NewVmService(providerData.Client)
+}
+
+func (d *VmDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, span := tracing.Start(ctx, "data.crunchloop_vm", "Read")
+	defer tracing.End(span, &resp.Diagnostics)
+
+	var data VmDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var vm *client.VirtualMachine
+
+	if !data.Id.IsNull() {
+		id, err := strconv.Atoi(data.Id.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("id"),
+				"Invalid Vm Identifier",
+				fmt.Sprintf("Vm identifier must be a number, got: %s", data.Id.ValueString()),
+			)
+			return
+		}
+
+		vm, err = d.service.GetVm(ctx, int32(id))
+		if err != nil {
+			addApiError(&resp.Diagnostics, err)
+			return
+		}
+	} else {
+		vms, err := d.service.ListVms(ctx)
+		if err != nil {
+			addApiError(&resp.Diagnostics, err)
+			return
+		}
+
+		for i := range vms {
+			if *vms[i].Name != data.Name.ValueString() {
+				continue
+			}
+
+			if vm != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("name"),
+					"Multiple Vms Found",
+					fmt.Sprintf("More than one Vm is named %s, look it up by id instead", data.Name.ValueString()),
+				)
+				return
+			}
+
+			vm = &vms[i]
+		}
+
+		if vm == nil {
+			resp.Diagnostics.AddError(
+				"API Error",
+				fmt.Sprintf("Vm with name %s was not found", data.Name.ValueString()),
+			)
+			return
+		}
+	}
+
+	data.vmModelToState(vm)
+
+	tflog.Trace(ctx, "read vm data source")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (d *VmDataSourceModel) vmModelToState(vm *client.VirtualMachine) {
+	d.Id = idPointerValue(vm.Id)
+	d.Name = types.StringPointerValue(vm.Name)
+	d.Status = types.StringPointerValue((*string)(vm.Status))
+	d.Cores = types.Int32PointerValue(vm.Cores)
+	d.MemoryMegabytes = types.Int32Null()
+	if vm.MemoryBytes != nil {
+		d.MemoryMegabytes = types.Int32Value(utils.BytesToMegabytes(*vm.MemoryBytes))
+	}
+
+	d.Host = nil
+	if vm.Host != nil {
+		d.Host = &vmHostModel{
+			Id:     idPointerValue(vm.Host.Id),
+			Name:   types.StringPointerValue(vm.Host.Name),
+			Status: types.StringPointerValue((*string)(vm.Host.Status)),
+		}
+	}
+
+	d.Vmi = nil
+	if vm.Vmi != nil {
+		d.Vmi = &vmVmiModel{
+			Id:   idPointerValue(vm.Vmi.Id),
+			Name: types.StringPointerValue(vm.Vmi.Name),
+		}
+	}
+
+	d.RootVolume = nil
+	if vm.RootVolume != nil {
+		d.RootVolume = &vmVolumeModel{
+			Id:        idPointerValue(vm.RootVolume.Id),
+			Name:      types.StringPointerValue(vm.RootVolume.Name),
+			SizeBytes: types.Int64PointerValue(vm.RootVolume.SizeBytes),
+			Status:    types.StringPointerValue((*string)(vm.RootVolume.Status)),
+		}
+	}
+
+	d.Nic = nil
+	if vm.Nic != nil {
+		d.Nic = &vmNicModel{
+			Id:        idPointerValue(vm.Nic.Id),
+			IpAddress: types.StringPointerValue(vm.Nic.IpAddress),
+			Dhcp:      types.BoolPointerValue(vm.Nic.Dhcp),
+		}
+	}
+}
+
+// vmDataSourceAttributes returns the computed attributes describing a vm.
+func vmDataSourceAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"id": schema.StringAttribute{
+			MarkdownDescription: "Vm identifier",
+			Computed:            true,
+		},
+		"name": schema.StringAttribute{
+			MarkdownDescription: "Vm name",
+			Computed:            true,
+		},
+		"status": schema.StringAttribute{
+			MarkdownDescription: "Vm status",
+			Computed:            true,
+		},
+		"cores": schema.Int32Attribute{
+			MarkdownDescription: "Virtual CPU cores",
+			Computed:            true,
+		},
+		"memory_megabytes": schema.Int32Attribute{
+			MarkdownDescription: "Memory (MiB)",
+			Computed:            true,
+		},
+		"host": schema.SingleNestedAttribute{
+			MarkdownDescription: "Host where the Vm runs",
+			Computed:            true,
+			Attributes: map[string]schema.Attribute{
+				"id": schema.StringAttribute{
+					MarkdownDescription: "Host identifier",
+					Computed:            true,
+				},
+				"name": schema.StringAttribute{
+					MarkdownDescription: "Host name",
+					Computed:            true,
+				},
+				"status": schema.StringAttribute{
+					MarkdownDescription: "Host status",
+					Computed:            true,
+				},
+			},
+		},
+		"vmi": schema.SingleNestedAttribute{
+			MarkdownDescription: "Vmi the Vm was created from",
+			Computed:            true,
+			Attributes: map[string]schema.Attribute{
+				"id": schema.StringAttribute{
+					MarkdownDescription: "Vmi identifier",
+					Computed:            true,
+				},
+				"name": schema.StringAttribute{
+					MarkdownDescription: "Vmi name",
+					Computed:            true,
+				},
+			},
+		},
+		"root_volume": schema.SingleNestedAttribute{
+			MarkdownDescription: "Root volume of the Vm",
+			Computed:            true,
+			Attributes: map[string]schema.Attribute{
+				"id": schema.StringAttribute{
+					MarkdownDescription: "Volume identifier",
+					Computed:            true,
+				},
+				"name": schema.StringAttribute{
+					MarkdownDescription: "Volume name",
+					Computed:            true,
+				},
+				"size_bytes": schema.Int64Attribute{
+					MarkdownDescription: "Volume size (bytes)",
+					Computed:            true,
+				},
+				"status": schema.StringAttribute{
+					MarkdownDescription: "Volume status",
+					Computed:            true,
+				},
+			},
+		},
+		"nic": schema.SingleNestedAttribute{
+			MarkdownDescription: "Network interface of the Vm",
+			Computed:            true,
+			Attributes: map[string]schema.Attribute{
+				"id": schema.StringAttribute{
+					MarkdownDescription: "Network interface identifier",
+					Computed:            true,
+				},
+				"ip_address": schema.StringAttribute{
+					MarkdownDescription: "IP address assigned to the Vm",
+					Computed:            true,
+				},
+				"dhcp": schema.BoolAttribute{
+					MarkdownDescription: "Whether the IP address was assigned through DHCP",
+					Computed:            true,
+				},
+			},
+		},
+	}
+}
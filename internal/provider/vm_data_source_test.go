@@ -0,0 +1,109 @@
+package provider_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/acctest"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccVmDataSource(t *testing.T) {
+	server, url := acctest.NewServer(t)
+	server.AddHost("host-1", client.Online)
+	vmi := server.AddVmi("ubuntu-jammy")
+
+	config := acctest.ProviderConfig(url) + testAccVmsConfig(*vmi.Id, "web", "db")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Lookup by id
+			{
+				Config: config + `
+data "crunchloop_vm" "test" {
+  id = crunchloop_vm.test_0.id
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.crunchloop_vm.test", "id", "crunchloop_vm.test_0", "id"),
+					resource.TestCheckResourceAttr("data.crunchloop_vm.test", "name", "web"),
+					resource.TestCheckResourceAttr("data.crunchloop_vm.test", "status", "running"),
+					resource.TestCheckResourceAttr("data.crunchloop_vm.test", "cores", "1"),
+					resource.TestCheckResourceAttr("data.crunchloop_vm.test", "memory_megabytes", "512"),
+					resource.TestCheckResourceAttr("data.crunchloop_vm.test", "host.name", "host-1"),
+					resource.TestCheckResourceAttr("data.crunchloop_vm.test", "vmi.id", fmt.Sprint(*vmi.Id)),
+				),
+			},
+			// Lookup by name, deferred until the vms exist
+			{
+				Config: config + `
+data "crunchloop_vm" "test" {
+  name = "db"
+
+  depends_on = [crunchloop_vm.test_1]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.crunchloop_vm.test", "id", "crunchloop_vm.test_1", "id"),
+					resource.TestCheckResourceAttr("data.crunchloop_vm.test", "name", "db"),
+				),
+			},
+			{
+				Config: acctest.ProviderConfig(url) + testAccVmsConfig(*vmi.Id, "web", "db", "web") + `
+data "crunchloop_vm" "test" {
+  name = "web"
+
+  depends_on = [crunchloop_vm.test_2]
+}
+`,
+				ExpectError: regexp.MustCompile("Multiple Vms Found"),
+			},
+			{
+				Config: config + `
+data "crunchloop_vm" "test" {
+  name = "cache"
+
+  depends_on = [crunchloop_vm.test_1]
+}
+`,
+				ExpectError: regexp.MustCompile("Vm with name cache was not found"),
+			},
+			{
+				Config: config + `
+data "crunchloop_vm" "test" {
+  id = "999"
+}
+`,
+				ExpectError: regexp.MustCompile("Not Found"),
+			},
+		},
+	})
+}
+
+// testAccVmsConfig returns crunchloop_vm.test_<index> resources with the given
+// names. Each one depends on the previous one so they are created, and get
+// their ids, in order.
+func testAccVmsConfig(vmiId int32, names ...string) string {
+	var config string
+	for i, name := range names {
+		var dependsOn string
+		if i > 0 {
+			dependsOn = fmt.Sprintf("\n  depends_on = [crunchloop_vm.test_%d]\n", i-1)
+		}
+
+		config += fmt.Sprintf(`
+resource "crunchloop_vm" "test_%d" {
+  name                       = %q
+  vmi_id                     = %d
+  cores                      = 1
+  memory_megabytes           = 512
+  root_volume_size_gigabytes = 10
+%s}
+`, i, name, vmiId, dependsOn)
+	}
+
+	return config
+}
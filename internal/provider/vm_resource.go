@@ -4,43 +4,81 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
 	"github.com/crunchloop/terraform-provider-crunchloop/internal/services"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/tracing"
 	"github.com/crunchloop/terraform-provider-crunchloop/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
+// vmVolumeAttributeTypes describes the root_volume object. The object is
+// unknown until the vm is created, so it can't be decoded into a struct.
+var vmVolumeAttributeTypes = map[string]attr.Type{
+	"id":         types.StringType,
+	"name":       types.StringType,
+	"size_bytes": types.Int64Type,
+	"status":     types.StringType,
+}
+
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &VmResource{}
 var _ resource.ResourceWithImportState = &VmResource{}
 
+// vmRequestAttributes maps the fields of the create and update requests to
+// the attributes they're built from, so input errors point at them.
+var vmRequestAttributes = map[string]path.Path{
+	"name":                       path.Root("name"),
+	"vmi_id":                     path.Root("vmi_id"),
+	"host_id":                    path.Root("host_id"),
+	"cores":                      path.Root("cores"),
+	"memory_megabytes":           path.Root("memory_megabytes"),
+	"root_volume_size_gigabytes": path.Root("root_volume_size_gigabytes"),
+	"ssh_key":                    path.Root("ssh_key"),
+	"user_data":                  path.Root("user_data"),
+}
+
 func NewVmResource() resource.Resource {
 	return &VmResource{}
 }
 
 // VmResource defines the resource implementation.
 type VmResource struct {
-	service *services.VmService
+	service        *services.VmService
+	defaultTimeout time.Duration
 }
 
 // VmResourceModel describes the resource data model.
 type VmResourceModel struct {
-	Id                      types.String `tfsdk:"id"`
-	Name                    types.String `tfsdk:"name"`
-	MemoryMegabytes         types.Int32  `tfsdk:"memory_megabytes"`
-	Cores                   types.Int32  `tfsdk:"cores"`
-	VmiId                   types.Int32  `tfsdk:"vmi_id"`
-	HostId                  types.Int32  `tfsdk:"host_id"`
-	RootVolumeSizeGigabytes types.Int32  `tfsdk:"root_volume_size_gigabytes"`
-	UserData                types.String `tfsdk:"user_data"`
-	SshKey                  types.String `tfsdk:"ssh_key"`
+	Id                      types.String   `tfsdk:"id"`
+	Name                    types.String   `tfsdk:"name"`
+	MemoryMegabytes         types.Int32    `tfsdk:"memory_megabytes"`
+	Cores                   types.Int32    `tfsdk:"cores"`
+	VmiId                   types.Int32    `tfsdk:"vmi_id"`
+	HostId                  types.Int32    `tfsdk:"host_id"`
+	RootVolumeSizeGigabytes types.Int32    `tfsdk:"root_volume_size_gigabytes"`
+	UserData                types.String   `tfsdk:"user_data"`
+	SshKey                  types.String   `tfsdk:"ssh_key"`
+	WaitForIpAddress        types.Bool     `tfsdk:"wait_for_ip_address"`
+	NicId                   types.String   `tfsdk:"nic_id"`
+	IpAddress               types.String   `tfsdk:"ip_address"`
+	Dhcp                    types.Bool     `tfsdk:"dhcp"`
+	Status                  types.String   `tfsdk:"status"`
+	RootVolume              types.Object   `tfsdk:"root_volume"`
+	Timeouts                timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *VmResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -70,10 +108,16 @@ func (r *VmResource) Schema(ctx context.Context, req resource.SchemaRequest, res
 			"memory_megabytes": schema.Int32Attribute{
 				Required:            true,
 				MarkdownDescription: "Memory (MiB)",
+				Validators: []validator.Int32{
+					int32validator.Between(client.CreateVmMemoryMegabytesMinimum, client.CreateVmMemoryMegabytesMaximum),
+				},
 			},
 			"cores": schema.Int32Attribute{
 				Required:            true,
 				MarkdownDescription: "Virtual CPU cores",
+				Validators: []validator.Int32{
+					int32validator.Between(client.CreateVmCoresMinimum, client.CreateVmCoresMaximum),
+				},
 			},
 			"vmi_id": schema.Int32Attribute{
 				Required:            true,
@@ -93,6 +137,9 @@ func (r *VmResource) Schema(ctx context.Context, req resource.SchemaRequest, res
 			"root_volume_size_gigabytes": schema.Int32Attribute{
 				Required:            true,
 				MarkdownDescription: "Root volume size (GiB)",
+				Validators: []validator.Int32{
+					int32validator.Between(client.CreateVmRootVolumeSizeGigabytesMinimum, client.CreateVmRootVolumeSizeGigabytesMaximum),
+				},
 				PlanModifiers: []planmodifier.Int32{
 					int32planmodifier.RequiresReplace(),
 				},
@@ -100,6 +147,9 @@ func (r *VmResource) Schema(ctx context.Context, req resource.SchemaRequest, res
 			"ssh_key": schema.StringAttribute{
 				Optional:            true,
 				MarkdownDescription: "Ssh public key to authenticate with the Vm",
+				Validators: []validator.String{
+					sshPublicKeyValidator{},
+				},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
@@ -107,7 +157,68 @@ func (r *VmResource) Schema(ctx context.Context, req resource.SchemaRequest, res
 			"user_data": schema.StringAttribute{
 				Optional:            true,
 				MarkdownDescription: "Cloud init user data shell script, base64 encoded",
+				Validators: []validator.String{
+					base64Validator{},
+				},
+			},
+			"wait_for_ip_address": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Wait until an IP address is assigned to the Vm before finishing the creation. Defaults to `false`",
+			},
+			"nic_id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identifier of the Vm network interface",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
+			// The address isn't kept from state on updates, updating the vm
+			// restarts it and it may lease a different one.
+			"ip_address": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "IP address assigned to the Vm, it may be empty until the Vm leases one unless `wait_for_ip_address` is set",
+			},
+			"dhcp": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Whether the IP address is assigned through DHCP",
+			},
+			"status": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Vm status, e.g. `running`, `stopped` or `suspended`. Use `crunchloop_vm_state` to manage it",
+			},
+			"root_volume": schema.SingleNestedAttribute{
+				Computed:            true,
+				MarkdownDescription: "Root volume of the Vm",
+				PlanModifiers: []planmodifier.Object{
+					objectplanmodifier.UseStateForUnknown(),
+				},
+				Attributes: map[string]schema.Attribute{
+					"id": schema.StringAttribute{
+						Computed:            true,
+						MarkdownDescription: "Volume identifier",
+					},
+					"name": schema.StringAttribute{
+						Computed:            true,
+						MarkdownDescription: "Volume name",
+					},
+					"size_bytes": schema.Int64Attribute{
+						Computed:            true,
+						MarkdownDescription: "Exact volume size (bytes)",
+					},
+					"status": schema.StringAttribute{
+						Computed:            true,
+						MarkdownDescription: "Volume status",
+					},
+				},
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
 		},
 	}
 }
@@ -118,21 +229,25 @@ func (r *VmResource) Configure(ctx context.Context, req resource.ConfigureReques
 		return
 	}
 
-	client, ok := req.ProviderData.(*client.ClientWithResponses)
+	providerData, ok := req.ProviderData.(*CrunchloopProviderData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *client.ClientWithResponses, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *CrunchloopProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.service = services.NewVmService(client)
+	r.service = services.NewVmService(providerData.Client)
+	r.defaultTimeout = providerData.DefaultTimeout
 }
 
 func (r *VmResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := tracing.Start(ctx, "crunchloop_vm", "Create")
+	defer tracing.End(span, &resp.Diagnostics)
+
 	var data VmResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
@@ -140,6 +255,15 @@ func (r *VmResource) Create(ctx context.Context, req resource.CreateRequest, res
 		return
 	}
 
+	createTimeout, diags := data.Timeouts.Create(ctx, r.defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
 	createOptions := client.CreateVmJSONRequestBody{
 		Name:                    data.Name.ValueString(),
 		MemoryMegabytes:         data.MemoryMegabytes.ValueInt32(),
@@ -162,15 +286,38 @@ func (r *VmResource) Create(ctx context.Context, req resource.CreateRequest, res
 
 	vm, err := r.service.CreateVm(ctx, createOptions)
 	if err != nil {
-		resp.Diagnostics.AddError("API Error", err.Error())
+		// The vm may have been created, keep it in state so it's tainted
+		// instead of leaked
+		if vm != nil {
+			data.vmModelToStateResource(vm)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		}
+
+		addApiErrorWithAttributes(&resp.Diagnostics, err, vmRequestAttributes)
 		return
 	}
 
+	if data.WaitForIpAddress.ValueBool() {
+		vmWithIpAddress, err := r.service.WaitForVmIpAddress(ctx, *vm.Id)
+		if err != nil {
+			// The vm was created, keep it in state so it's not leaked
+			data.vmModelToStateResource(vm)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			addApiError(&resp.Diagnostics, err)
+			return
+		}
+
+		vm = vmWithIpAddress
+	}
+
 	data.vmModelToStateResource(vm)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *VmResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := tracing.Start(ctx, "crunchloop_vm", "Read")
+	defer tracing.End(span, &resp.Diagnostics)
+
 	var data VmResourceModel
 
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
@@ -181,7 +328,13 @@ func (r *VmResource) Read(ctx context.Context, req resource.ReadRequest, resp *r
 	id, _ := strconv.Atoi(data.Id.ValueString())
 	vm, err := r.service.GetVm(ctx, int32(id))
 	if err != nil {
-		resp.Diagnostics.AddError("API Error", err.Error())
+		if services.IsNotFound(err) {
+			tflog.Warn(ctx, fmt.Sprintf("Vm %d not found, removing it from state", id))
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		addApiError(&resp.Diagnostics, err)
 		return
 	}
 
@@ -190,32 +343,72 @@ func (r *VmResource) Read(ctx context.Context, req resource.ReadRequest, resp *r
 }
 
 func (r *VmResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var data VmResourceModel
+	ctx, span := tracing.Start(ctx, "crunchloop_vm", "Update")
+	defer tracing.End(span, &resp.Diagnostics)
+
+	var data, state VmResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	updateOptions := client.UpdateVmJSONRequestBody{
-		MemoryMegabytes: data.MemoryMegabytes.ValueInt32Pointer(),
-		Cores:           data.Cores.ValueInt32Pointer(),
-		UserData:        data.UserData.ValueStringPointer(),
+	updateTimeout, diags := data.Timeouts.Update(ctx, r.defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
 	// Parse the vm id
 	id, _ := strconv.Atoi(data.Id.ValueString())
-	vm, err := r.service.UpdateVm(ctx, int32(id), updateOptions)
+
+	var vm *client.VirtualMachine
+	var err error
+
+	// Updating the vm restarts it, skip it when only provider settings
+	// changed, e.g. timeouts or wait_for_ip_address.
+	if data.Cores.Equal(state.Cores) && data.MemoryMegabytes.Equal(state.MemoryMegabytes) && data.UserData.Equal(state.UserData) {
+		vm, err = r.service.GetVm(ctx, int32(id))
+	} else {
+		vm, err = r.service.UpdateVm(ctx, int32(id), client.UpdateVmJSONRequestBody{
+			MemoryMegabytes: data.MemoryMegabytes.ValueInt32Pointer(),
+			Cores:           data.Cores.ValueInt32Pointer(),
+			UserData:        data.UserData.ValueStringPointer(),
+		})
+	}
+
 	if err != nil {
-		resp.Diagnostics.AddError("API Error", err.Error())
+		addApiErrorWithAttributes(&resp.Diagnostics, err, vmRequestAttributes)
 		return
 	}
 
+	// Like on create, wait until the vm leased an address, e.g. again after
+	// the update restarted it.
+	if data.WaitForIpAddress.ValueBool() && vm.Status != nil && *vm.Status == client.VirtualMachineStatusRunning && (vm.Nic == nil || vm.Nic.IpAddress == nil || *vm.Nic.IpAddress == "") {
+		vmWithIpAddress, err := r.service.WaitForVmIpAddress(ctx, int32(id))
+		if err != nil {
+			// The vm was updated, keep the state in sync with it
+			data.vmModelToStateResource(vm)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			addApiError(&resp.Diagnostics, err)
+			return
+		}
+
+		vm = vmWithIpAddress
+	}
+
 	data.vmModelToStateResource(vm)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *VmResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := tracing.Start(ctx, "crunchloop_vm", "Delete")
+	defer tracing.End(span, &resp.Diagnostics)
+
 	var data VmResourceModel
 
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
@@ -223,11 +416,20 @@ func (r *VmResource) Delete(ctx context.Context, req resource.DeleteRequest, res
 		return
 	}
 
+	deleteTimeout, diags := data.Timeouts.Delete(ctx, r.defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
 	// Parse the vm id
 	id, _ := strconv.Atoi(data.Id.ValueString())
 	err := r.service.DeleteVm(ctx, int32(id))
-	if err != nil {
-		resp.Diagnostics.AddError("API Error", err.Error())
+	if err != nil && !services.IsNotFound(err) {
+		addApiError(&resp.Diagnostics, err)
 		return
 	}
 }
@@ -237,11 +439,46 @@ func (r *VmResource) ImportState(ctx context.Context, req resource.ImportStateRe
 }
 
 func (d *VmResourceModel) vmModelToStateResource(vm *client.VirtualMachine) {
-	d.Id = types.StringValue(strconv.Itoa(int(*vm.Id)))
-	d.Name = types.StringValue(*vm.Name)
-	d.VmiId = types.Int32Value(*vm.Vmi.Id)
-	d.HostId = types.Int32Value(*vm.Host.Id)
-	d.Cores = types.Int32Value(*vm.Cores)
-	d.MemoryMegabytes = types.Int32Value(utils.BytesToMegabytes(*vm.MemoryBytes))
-	d.RootVolumeSizeGigabytes = types.Int32Value(utils.BytesToGigabytes(*vm.RootVolume.SizeBytes))
+	d.Id = idPointerValue(vm.Id)
+	d.Name = types.StringPointerValue(vm.Name)
+	d.Cores = types.Int32PointerValue(vm.Cores)
+	d.Status = types.StringPointerValue((*string)(vm.Status))
+	d.MemoryMegabytes = types.Int32Null()
+	if vm.MemoryBytes != nil {
+		d.MemoryMegabytes = types.Int32Value(utils.BytesToMegabytes(*vm.MemoryBytes))
+	}
+
+	d.VmiId = types.Int32Null()
+	if vm.Vmi != nil {
+		d.VmiId = types.Int32PointerValue(vm.Vmi.Id)
+	}
+
+	d.HostId = types.Int32Null()
+	if vm.Host != nil {
+		d.HostId = types.Int32PointerValue(vm.Host.Id)
+	}
+
+	d.RootVolumeSizeGigabytes = types.Int32Null()
+	d.RootVolume = types.ObjectNull(vmVolumeAttributeTypes)
+	if vm.RootVolume != nil {
+		if vm.RootVolume.SizeBytes != nil {
+			d.RootVolumeSizeGigabytes = types.Int32Value(utils.BytesToGigabytes(*vm.RootVolume.SizeBytes))
+		}
+
+		d.RootVolume = types.ObjectValueMust(vmVolumeAttributeTypes, map[string]attr.Value{
+			"id":         idPointerValue(vm.RootVolume.Id),
+			"name":       types.StringPointerValue(vm.RootVolume.Name),
+			"size_bytes": types.Int64PointerValue(vm.RootVolume.SizeBytes),
+			"status":     types.StringPointerValue((*string)(vm.RootVolume.Status)),
+		})
+	}
+
+	d.NicId = types.StringNull()
+	d.IpAddress = types.StringNull()
+	d.Dhcp = types.BoolNull()
+	if vm.Nic != nil {
+		d.NicId = idPointerValue(vm.Nic.Id)
+		d.IpAddress = types.StringPointerValue(vm.Nic.IpAddress)
+		d.Dhcp = types.BoolPointerValue(vm.Nic.Dhcp)
+	}
 }
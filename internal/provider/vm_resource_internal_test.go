@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
+)
+
+// The create response of a vm that fails to become running is stored in
+// state, it may lack everything the API only fills in once the vm is placed.
+func TestVmModelToStateResource_partial(t *testing.T) {
+	id := int32(3)
+	status := client.VirtualMachineStatusCreating
+
+	var data VmResourceModel
+	data.vmModelToStateResource(&client.VirtualMachine{Id: &id, Status: &status})
+
+	if data.Id.ValueString() != "3" {
+		t.Errorf("expected id %q, got %s", "3", data.Id)
+	}
+
+	if data.Status.ValueString() != "creating" {
+		t.Errorf("expected status %q, got %s", "creating", data.Status)
+	}
+
+	for name, isNull := range map[string]bool{
+		"name":                       data.Name.IsNull(),
+		"vmi_id":                     data.VmiId.IsNull(),
+		"host_id":                    data.HostId.IsNull(),
+		"cores":                      data.Cores.IsNull(),
+		"memory_megabytes":           data.MemoryMegabytes.IsNull(),
+		"root_volume_size_gigabytes": data.RootVolumeSizeGigabytes.IsNull(),
+		"root_volume":                data.RootVolume.IsNull(),
+		"nic_id":                     data.NicId.IsNull(),
+	} {
+		if !isNull {
+			t.Errorf("expected %s to be null", name)
+		}
+	}
+}
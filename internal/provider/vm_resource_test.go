@@ -0,0 +1,229 @@
+package provider_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/acctest"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccVmResource(t *testing.T) {
+	server, url := acctest.NewServer(t)
+	host := server.AddHost("host-1", client.Online)
+	vmi := server.AddVmi("ubuntu-jammy")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			for _, rs := range s.RootModule().Resources {
+				if rs.Type != "crunchloop_vm" {
+					continue
+				}
+
+				var id int32
+				if _, err := fmt.Sscan(rs.Primary.ID, &id); err != nil {
+					return fmt.Errorf("invalid vm id %q: %w", rs.Primary.ID, err)
+				}
+
+				if _, ok := server.Vm(id); ok {
+					return fmt.Errorf("vm %d still exists", id)
+				}
+			}
+
+			return nil
+		},
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: acctest.ProviderConfig(url) + testAccVmResourceConfig(*vmi.Id, 1, 512),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("crunchloop_vm.test", "id", "3"),
+					resource.TestCheckResourceAttr("crunchloop_vm.test", "name", "test"),
+					resource.TestCheckResourceAttr("crunchloop_vm.test", "host_id", fmt.Sprint(*host.Id)),
+					resource.TestCheckResourceAttr("crunchloop_vm.test", "cores", "1"),
+					resource.TestCheckResourceAttr("crunchloop_vm.test", "memory_megabytes", "512"),
+					resource.TestCheckResourceAttr("crunchloop_vm.test", "status", "running"),
+					resource.TestCheckResourceAttr("crunchloop_vm.test", "ip_address", "10.0.0.5"),
+					resource.TestCheckResourceAttr("crunchloop_vm.test", "root_volume.size_bytes", "10737418240"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "crunchloop_vm.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: acctest.ProviderConfig(url) + testAccVmResourceConfig(*vmi.Id, 2, 1024),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("crunchloop_vm.test", plancheck.ResourceActionUpdate),
+						// The vm restarts and may lease a different address.
+						plancheck.ExpectUnknownValue("crunchloop_vm.test", tfjsonpath.New("ip_address")),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("crunchloop_vm.test", "id", "3"),
+					resource.TestCheckResourceAttr("crunchloop_vm.test", "cores", "2"),
+					resource.TestCheckResourceAttr("crunchloop_vm.test", "memory_megabytes", "1024"),
+					resource.TestCheckResourceAttr("crunchloop_vm.test", "status", "running"),
+					resource.TestCheckResourceAttr("crunchloop_vm.test", "ip_address", "10.1.0.5"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccVmResource_removedOutsideTerraform(t *testing.T) {
+	server, url := acctest.NewServer(t)
+	server.AddHost("host-1", client.Online)
+	vmi := server.AddVmi("ubuntu-jammy")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(url) + testAccVmResourceConfig(*vmi.Id, 1, 512),
+				Check:  resource.TestCheckResourceAttr("crunchloop_vm.test", "id", "3"),
+			},
+			// A vm deleted outside of Terraform is dropped from state and
+			// created again.
+			{
+				PreConfig: func() {
+					if !server.RemoveVm(3) {
+						t.Fatal("vm 3 not found")
+					}
+				},
+				Config: acctest.ProviderConfig(url) + testAccVmResourceConfig(*vmi.Id, 1, 512),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("crunchloop_vm.test", plancheck.ResourceActionCreate),
+					},
+				},
+				Check: resource.TestCheckResourceAttrWith("crunchloop_vm.test", "id", func(value string) error {
+					if value == "3" {
+						return fmt.Errorf("expected a new vm, got vm %s", value)
+					}
+
+					return nil
+				}),
+			},
+		},
+	})
+}
+
+func TestAccVmResource_createTimeout(t *testing.T) {
+	server, url := acctest.NewServer(t)
+	server.AddHost("host-1", client.Online)
+	vmi := server.AddVmi("ubuntu-jammy")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// The vm is kept in state when it isn't running in time, so
+			// it's not leaked.
+			{
+				Config:      acctest.ProviderConfig(url) + testAccVmResourceConfigWithCreateTimeout(*vmi.Id, "1s"),
+				ExpectError: regexp.MustCompile("timeout waiting for vm 3"),
+			},
+			{
+				Config: acctest.ProviderConfig(url) + testAccVmResourceConfigWithCreateTimeout(*vmi.Id, "10m"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("crunchloop_vm.test", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+				Check: func(*terraform.State) error {
+					if _, ok := server.Vm(3); ok {
+						return fmt.Errorf("vm 3 wasn't replaced")
+					}
+
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccVmResource_providerSettings(t *testing.T) {
+	server, url := acctest.NewServer(t)
+	server.AddHost("host-1", client.Online)
+	vmi := server.AddVmi("ubuntu-jammy")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(url) + testAccVmResourceConfigWithCreateTimeout(*vmi.Id, "10m"),
+			},
+			// Changing settings the API doesn't know about doesn't update,
+			// and restart, the vm.
+			{
+				Config: acctest.ProviderConfig(url) + fmt.Sprintf(`
+resource "crunchloop_vm" "test" {
+  name                       = "test"
+  vmi_id                     = %d
+  cores                      = 1
+  memory_megabytes           = 512
+  root_volume_size_gigabytes = 10
+  wait_for_ip_address        = true
+
+  timeouts {
+    create = "20m"
+  }
+}
+`, *vmi.Id),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("crunchloop_vm.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: func(*terraform.State) error {
+					for _, request := range server.Requests() {
+						if request == "PUT /api/v1/vms/3" {
+							return fmt.Errorf("vm 3 was updated")
+						}
+					}
+
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func testAccVmResourceConfig(vmiId int32, cores int, memoryMegabytes int) string {
+	return fmt.Sprintf(`
+resource "crunchloop_vm" "test" {
+  name                       = "test"
+  vmi_id                     = %d
+  cores                      = %d
+  memory_megabytes           = %d
+  root_volume_size_gigabytes = 10
+}
+`, vmiId, cores, memoryMegabytes)
+}
+
+func testAccVmResourceConfigWithCreateTimeout(vmiId int32, timeout string) string {
+	return fmt.Sprintf(`
+resource "crunchloop_vm" "test" {
+  name                       = "test"
+  vmi_id                     = %d
+  cores                      = 1
+  memory_megabytes           = 512
+  root_volume_size_gigabytes = 10
+
+  timeouts {
+    create = %q
+  }
+}
+`, vmiId, timeout)
+}
@@ -2,20 +2,39 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
 	"github.com/crunchloop/terraform-provider-crunchloop/internal/services"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/tracing"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
+// vmStateStatuses are the statuses a vm can be driven to through
+// crunchloop_vm_state. The API reports suspended vms but has no endpoint to
+// suspend them, so suspended isn't offered until it does.
+var vmStateStatuses = []string{
+	string(client.VirtualMachineStatusRunning),
+	string(client.VirtualMachineStatusStopped),
+}
+
+// privateKeyDesiredStatus is the private state key holding the status last
+// applied by Terraform, used to tell drift apart from configuration changes.
+const privateKeyDesiredStatus = "desired_status"
+
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &VmStateResource{}
 var _ resource.ResourceWithImportState = &VmStateResource{}
@@ -26,13 +45,16 @@ func NewVmStateResource() resource.Resource {
 
 // VmStateResource defines the resource implementation.
 type VmStateResource struct {
-	service *services.VmService
+	service        *services.VmService
+	defaultTimeout time.Duration
 }
 
 // VmStateResourceModel describes the resource data model.
 type VmStateResourceModel struct {
-	VmId   types.String `tfsdk:"vm_id"`
-	Status types.String `tfsdk:"status"`
+	VmId           types.String   `tfsdk:"vm_id"`
+	Status         types.String   `tfsdk:"status"`
+	RebootTriggers types.Map      `tfsdk:"reboot_triggers"`
+	Timeouts       timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *VmStateResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -54,9 +76,28 @@ func (r *VmStateResource) Schema(ctx context.Context, req resource.SchemaRequest
 			},
 			"status": schema.StringAttribute{
 				Required:            true,
-				MarkdownDescription: "Vm status",
+				MarkdownDescription: "Desired Vm status, one of `running` or `stopped`",
+				Validators: []validator.String{
+					stringvalidator.OneOf(vmStateStatuses...),
+				},
+				PlanModifiers: []planmodifier.String{
+					vmStatusDriftModifier{},
+				},
+			},
+			"reboot_triggers": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				MarkdownDescription: "Arbitrary map of values that, when changed, reboots the Vm and waits until it is running again. " +
+					"Useful to roll out configuration changes, e.g. `user_data`, without replacing the Vm. Ignored unless `status` is `running`.",
 			},
 		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+			}),
+		},
 	}
 }
 
@@ -66,21 +107,25 @@ func (r *VmStateResource) Configure(ctx context.Context, req resource.ConfigureR
 		return
 	}
 
-	client, ok := req.ProviderData.(*client.ClientWithResponses)
+	providerData, ok := req.ProviderData.(*CrunchloopProviderData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *client.ClientWithResponses, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *CrunchloopProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.service = services.NewVmService(client)
+	r.service = services.NewVmService(providerData.Client)
+	r.defaultTimeout = providerData.DefaultTimeout
 }
 
 func (r *VmStateResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := tracing.Start(ctx, "crunchloop_vm_state", "Create")
+	defer tracing.End(span, &resp.Diagnostics)
+
 	var data VmStateResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
@@ -88,17 +133,30 @@ func (r *VmStateResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
+	createTimeout, diags := data.Timeouts.Create(ctx, r.defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
 	vm, err := r.updateVmStatus(ctx, &data)
 	if err != nil {
-		resp.Diagnostics.AddError("API Error", err.Error())
+		addApiError(&resp.Diagnostics, err)
 		return
 	}
 
 	data.vmModelToStateResource(vm)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, privateKeyDesiredStatus, desiredStatusPrivateValue(string(*vm.Status)))...)
 }
 
 func (r *VmStateResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := tracing.Start(ctx, "crunchloop_vm_state", "Read")
+	defer tracing.End(span, &resp.Diagnostics)
+
 	var data VmStateResourceModel
 
 	// Read Terraform prior state data into the model
@@ -110,7 +168,13 @@ func (r *VmStateResource) Read(ctx context.Context, req resource.ReadRequest, re
 	id, _ := strconv.Atoi(data.VmId.ValueString())
 	vm, err := r.service.GetVm(ctx, int32(id))
 	if err != nil {
-		resp.Diagnostics.AddError("API Error", err.Error())
+		if services.IsNotFound(err) {
+			tflog.Warn(ctx, fmt.Sprintf("Vm %d not found, removing it from state", id))
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		addApiError(&resp.Diagnostics, err)
 		return
 	}
 
@@ -119,24 +183,54 @@ func (r *VmStateResource) Read(ctx context.Context, req resource.ReadRequest, re
 }
 
 func (r *VmStateResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var data VmStateResourceModel
+	ctx, span := tracing.Start(ctx, "crunchloop_vm_state", "Update")
+	defer tracing.End(span, &resp.Diagnostics)
+
+	var data, state VmStateResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	updateTimeout, diags := data.Timeouts.Update(ctx, r.defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
 	vm, err := r.updateVmStatus(ctx, &data)
 	if err != nil {
-		resp.Diagnostics.AddError("API Error", err.Error())
+		addApiError(&resp.Diagnostics, err)
 		return
 	}
 
+	// A vm that was just started already booted with the latest
+	// configuration, it only needs a reboot if it was running before.
+	if !data.RebootTriggers.IsNull() && !data.RebootTriggers.Equal(state.RebootTriggers) &&
+		*vm.Status == client.VirtualMachineStatusRunning && state.Status.ValueString() == string(client.VirtualMachineStatusRunning) {
+		tflog.Debug(ctx, fmt.Sprintf("Reboot triggers changed, rebooting vm: %s", data.VmId.String()))
+
+		vm, err = r.service.RebootVm(ctx, *vm.Id)
+		if err != nil {
+			addApiError(&resp.Diagnostics, err)
+			return
+		}
+	}
+
 	data.vmModelToStateResource(vm)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, privateKeyDesiredStatus, desiredStatusPrivateValue(string(*vm.Status)))...)
 }
 
 func (r *VmStateResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := tracing.Start(ctx, "crunchloop_vm_state", "Delete")
+	defer tracing.End(span, &resp.Diagnostics)
+
 	var data VmStateResourceModel
 
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
@@ -159,22 +253,71 @@ func (r *VmStateResource) updateVmStatus(ctx context.Context, data *VmStateResou
 		return nil, err
 	}
 
-	if data.Status.String() == string(client.VirtualMachineStatusRunning) && *vm.Status == client.VirtualMachineStatusRunning {
-		tflog.Debug(ctx, fmt.Sprintf("Vm is already running: %s", data.VmId.String()))
+	desired := client.VirtualMachineStatus(data.Status.ValueString())
+	if *vm.Status == desired {
+		tflog.Debug(ctx, fmt.Sprintf("Vm %d is already %s", id, desired))
 		return vm, nil
 	}
 
-	if data.Status.String() == string(client.VirtualMachineStatusStopped) && *vm.Status == client.VirtualMachineStatusStopped {
-		tflog.Debug(ctx, fmt.Sprintf("Vm is already stopped: %s", data.VmId.String()))
-		return vm, nil
+	switch desired {
+	case client.VirtualMachineStatusStopped:
+		return r.service.StopVm(ctx, int32(id))
+	case client.VirtualMachineStatusRunning:
+		return r.service.StartVm(ctx, int32(id))
 	}
 
-	switch data.Status.ValueString() {
-	case "stopped":
-		vm, err = r.service.StopVm(ctx, int32(id))
-	case "running":
-		vm, err = r.service.StartVm(ctx, int32(id))
+	return nil, fmt.Errorf("unsupported vm status %q", desired)
+}
+
+func desiredStatusPrivateValue(status string) []byte {
+	value, _ := json.Marshal(status)
+	return value
+}
+
+var _ planmodifier.String = vmStatusDriftModifier{}
+
+// vmStatusDriftModifier warns when the vm status was changed outside of
+// Terraform since the last apply, so the plan explains why the status is
+// being transitioned even though the configuration didn't change.
+type vmStatusDriftModifier struct{}
+
+func (m vmStatusDriftModifier) Description(ctx context.Context) string {
+	return "Warns when the actual vm status drifted from the last applied status."
+}
+
+func (m vmStatusDriftModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m vmStatusDriftModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	if req.StateValue.IsNull() || req.StateValue.IsUnknown() || req.PlanValue.IsUnknown() {
+		return
+	}
+
+	rawDesired, diags := req.Private.GetKey(ctx, privateKeyDesiredStatus)
+	resp.Diagnostics.Append(diags...)
+	if rawDesired == nil {
+		return
+	}
+
+	var desired string
+	if err := json.Unmarshal(rawDesired, &desired); err != nil {
+		return
+	}
+
+	addVmStatusDriftWarning(&resp.Diagnostics, req.Path, desired, req.StateValue.ValueString(), req.PlanValue.ValueString())
+}
+
+// addVmStatusDriftWarning warns when the actual vm status isn't the one the
+// last apply left it in, planned is the status the plan transitions it to.
+func addVmStatusDriftWarning(diags *diag.Diagnostics, attributePath path.Path, desired, actual, planned string) {
+	if actual == desired {
+		return
 	}
 
-	return vm, err
+	diags.AddAttributeWarning(
+		attributePath,
+		"Vm Status Drift",
+		fmt.Sprintf("The vm was left %q by the last apply but it is currently %q. Applying this plan will transition it to %q.", desired, actual, planned),
+	)
 }
@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+func TestAddVmStatusDriftWarning(t *testing.T) {
+	cases := []struct {
+		name    string
+		desired string
+		actual  string
+		warning bool
+	}{
+		{
+			name:    "drifted",
+			desired: "running",
+			actual:  "stopped",
+			warning: true,
+		},
+		{
+			name:    "in sync",
+			desired: "running",
+			actual:  "running",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			addVmStatusDriftWarning(&diags, path.Root("status"), c.desired, c.actual, "running")
+
+			if !c.warning {
+				if len(diags) != 0 {
+					t.Fatalf("expected no diagnostics, got %v", diags)
+				}
+
+				return
+			}
+
+			if len(diags) != 1 {
+				t.Fatalf("expected 1 diagnostic, got %d: %v", len(diags), diags)
+			}
+
+			if diags[0].Severity() != diag.SeverityWarning {
+				t.Errorf("expected a warning, got %s", diags[0].Severity())
+			}
+
+			if diags[0].Summary() != "Vm Status Drift" {
+				t.Errorf("expected summary %q, got %q", "Vm Status Drift", diags[0].Summary())
+			}
+
+			expected := `The vm was left "running" by the last apply but it is currently "stopped". Applying this plan will transition it to "running".`
+			if diags[0].Detail() != expected {
+				t.Errorf("expected detail %q, got %q", expected, diags[0].Detail())
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package provider_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/acctest"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/mockapi"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccVmStateResource(t *testing.T) {
+	server, url := acctest.NewServer(t)
+	server.AddHost("host-1", client.Online)
+	vmi := server.AddVmi("ubuntu-jammy")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: acctest.ProviderConfig(url) + testAccVmStateResourceConfig(*vmi.Id, "stopped"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("crunchloop_vm_state.test", "vm_id", "crunchloop_vm.test", "id"),
+					resource.TestCheckResourceAttr("crunchloop_vm_state.test", "status", "stopped"),
+					testAccCheckVmStatus(server, "crunchloop_vm.test", client.VirtualMachineStatusStopped),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:                         "crunchloop_vm_state.test",
+				ImportState:                          true,
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "vm_id",
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					return s.RootModule().Resources["crunchloop_vm_state.test"].Primary.Attributes["vm_id"], nil
+				},
+			},
+			// Update and Read testing
+			{
+				Config: acctest.ProviderConfig(url) + testAccVmStateResourceConfig(*vmi.Id, "running"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("crunchloop_vm_state.test", "status", "running"),
+					testAccCheckVmStatus(server, "crunchloop_vm.test", client.VirtualMachineStatusRunning),
+				),
+			},
+			// A status changed outside of Terraform is transitioned back. The
+			// plan also warns about the drift, plan checks don't see
+			// diagnostics so the warning is covered by
+			// TestAddVmStatusDriftWarning.
+			{
+				PreConfig: func() {
+					if !server.SetVmStatus(3, client.VirtualMachineStatusStopped) {
+						t.Fatal("vm 3 not found")
+					}
+				},
+				Config: acctest.ProviderConfig(url) + testAccVmStateResourceConfig(*vmi.Id, "running"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("crunchloop_vm_state.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: testAccCheckVmStatus(server, "crunchloop_vm.test", client.VirtualMachineStatusRunning),
+			},
+			// The API has no endpoint to suspend a vm.
+			{
+				Config:      acctest.ProviderConfig(url) + testAccVmStateResourceConfig(*vmi.Id, "suspended"),
+				ExpectError: regexp.MustCompile(`value must be one of`),
+			},
+		},
+	})
+}
+
+func TestAccVmStateResource_rebootTriggers(t *testing.T) {
+	server, url := acctest.NewServer(t)
+	server.AddHost("host-1", client.Online)
+	vmi := server.AddVmi("ubuntu-jammy")
+
+	config := func(revision string) string {
+		return acctest.ProviderConfig(url) + testAccVmStateResourceConfig(*vmi.Id, "running") + fmt.Sprintf(`
+resource "crunchloop_vm_state" "reboot" {
+  vm_id  = crunchloop_vm.test.id
+  status = "running"
+
+  reboot_triggers = {
+    revision = %q
+  }
+}
+`, revision)
+	}
+
+	rebooted := func(*terraform.State) error {
+		for _, request := range server.Requests() {
+			if request == "POST /api/v1/vms/3/reboot" {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("vm 3 wasn't rebooted")
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config("1"),
+			},
+			{
+				Config: config("2"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("crunchloop_vm_state.reboot", "status", "running"),
+					testAccCheckVmStatus(server, "crunchloop_vm.test", client.VirtualMachineStatusRunning),
+					rebooted,
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckVmStatus checks the status of the vm as stored by the API.
+func testAccCheckVmStatus(server *mockapi.Server, resourceName string, status client.VirtualMachineStatus) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource %s not found", resourceName)
+		}
+
+		var id int32
+		if _, err := fmt.Sscan(rs.Primary.ID, &id); err != nil {
+			return fmt.Errorf("invalid vm id %q: %w", rs.Primary.ID, err)
+		}
+
+		vm, ok := server.Vm(id)
+		if !ok {
+			return fmt.Errorf("vm %d not found", id)
+		}
+
+		if *vm.Status != status {
+			return fmt.Errorf("expected vm %d to be %s, got %s", id, status, *vm.Status)
+		}
+
+		return nil
+	}
+}
+
+func testAccVmStateResourceConfig(vmiId int32, status string) string {
+	return fmt.Sprintf(`
+resource "crunchloop_vm" "test" {
+  name                       = "test"
+  vmi_id                     = %d
+  cores                      = 1
+  memory_megabytes           = 512
+  root_volume_size_gigabytes = 10
+}
+
+resource "crunchloop_vm_state" "test" {
+  vm_id  = crunchloop_vm.test.id
+  status = %q
+}
+`, vmiId, status)
+}
@@ -3,9 +3,9 @@ package provider
 import (
 	"context"
 	"fmt"
-	"strconv"
 
-	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/services"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/tracing"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -20,7 +20,7 @@ func NewVmiDataSource() datasource.DataSource {
 
 // VmiDataSource defines the data source implementation.
 type VmiDataSource struct {
-	client *client.ClientWithResponses
+	service *services.VmiService
 }
 
 // VmiDataSourceModel describes the data source data model.
@@ -57,39 +57,35 @@ func (d *VmiDataSource) Configure(ctx context.Context, req datasource.ConfigureR
 		return
 	}
 
-	client, ok := req.ProviderData.(*client.ClientWithResponses)
+	providerData, ok := req.ProviderData.(*CrunchloopProviderData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *CrunchloopProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	d.client = client
+	d.service = services.NewVmiService(providerData.Client)
 }
 
 func (d *VmiDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, span := tracing.Start(ctx, "data.crunchloop_vmi", "Read")
+	defer tracing.End(span, &resp.Diagnostics)
+
 	var data VmiDataSourceModel
 
 	// Read Terraform configuration data into the model
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 
-	response, err := d.client.ListVmisWithResponse(ctx)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"API Error",
-			fmt.Sprintf("Unable to read vmi, got error: %s", err),
-		)
-		return
-	}
-
-	for _, vmi := range *response.JSON200.Data {
-		if *vmi.Name == data.Name.ValueString() {
-			data.Id = types.StringValue(strconv.Itoa(int(*vmi.Id)))
-			data.Name = types.StringValue(*vmi.Name)
+	iterator := d.service.IterateVmis()
+	for iterator.Next(ctx) {
+		vmi := iterator.Value()
+		if vmi.Name != nil && *vmi.Name == data.Name.ValueString() {
+			data.Id = idPointerValue(vmi.Id)
+			data.Name = types.StringPointerValue(vmi.Name)
 
 			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 
@@ -97,6 +93,11 @@ func (d *VmiDataSource) Read(ctx context.Context, req datasource.ReadRequest, re
 		}
 	}
 
+	if err := iterator.Err(); err != nil {
+		addApiError(&resp.Diagnostics, err)
+		return
+	}
+
 	resp.Diagnostics.AddError(
 		"API Error",
 		fmt.Sprintf("Vmi with name %s was not found", data.Name.ValueString()),
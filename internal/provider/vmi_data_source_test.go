@@ -0,0 +1,38 @@
+package provider_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccVmiDataSource(t *testing.T) {
+	server, url := acctest.NewServer(t)
+	vmi := server.AddVmi("ubuntu-jammy")
+	server.AddVmi("debian-bookworm")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(url) + `
+data "crunchloop_vmi" "test" {
+  name = "ubuntu-jammy"
+}
+`,
+				Check: resource.TestCheckResourceAttr("data.crunchloop_vmi.test", "id", fmt.Sprint(*vmi.Id)),
+			},
+			{
+				Config: acctest.ProviderConfig(url) + `
+data "crunchloop_vmi" "test" {
+  name = "ubuntu-noble"
+}
+`,
+				ExpectError: regexp.MustCompile("not found"),
+			},
+		},
+	})
+}
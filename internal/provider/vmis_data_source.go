@@ -0,0 +1,169 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/services"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/tracing"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &VmisDataSource{}
+
+func NewVmisDataSource() datasource.DataSource {
+	return &VmisDataSource{}
+}
+
+// VmisDataSource defines the data source implementation.
+type VmisDataSource struct {
+	service *services.VmiService
+}
+
+// VmisDataSourceModel describes the data source data model.
+type VmisDataSourceModel struct {
+	NameRegex types.String   `tfsdk:"name_regex"`
+	Status    types.String   `tfsdk:"status"`
+	Vmis      []vmisVmiModel `tfsdk:"vmis"`
+}
+
+type vmisVmiModel struct {
+	Id     types.String `tfsdk:"id"`
+	Name   types.String `tfsdk:"name"`
+	Status types.String `tfsdk:"status"`
+}
+
+func (d *VmisDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vmis"
+}
+
+func (d *VmisDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Vmis data source, lists every Vmi matching the given filters. There is no object type filter, the API only lists vmis here",
+
+		Attributes: map[string]schema.Attribute{
+			"name_regex": schema.StringAttribute{
+				MarkdownDescription: "Regular expression the Vmi name must match",
+				Optional:            true,
+				Validators: []validator.String{
+					regexpValidator{},
+				},
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "Only return Vmis with this status, one of `creating`, `available`, `failed` or `deleting`",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						string(client.VirtualMachineImageStatusCreating),
+						string(client.VirtualMachineImageStatusAvailable),
+						string(client.VirtualMachineImageStatusFailed),
+						string(client.VirtualMachineImageStatusDeleting),
+					),
+				},
+			},
+			"vmis": schema.ListNestedAttribute{
+				MarkdownDescription: "Matching Vmis, sorted by id, i.e. in creation order",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "Vmi identifier",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Vmi name",
+							Computed:            true,
+						},
+						"status": schema.StringAttribute{
+							MarkdownDescription: "Vmi status",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *VmisDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*CrunchloopProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *CrunchloopProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.service = services.NewVmiService(providerData.Client)
+}
+
+func (d *VmisDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, span := tracing.Start(ctx, "data.crunchloop_vmis", "Read")
+	defer tracing.End(span, &resp.Diagnostics)
+
+	var data VmisDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	nameRegex, err := regexp.Compile(data.NameRegex.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Regular Expression", err.Error())
+		return
+	}
+
+	vmis, err := d.service.ListVmis(ctx)
+	if err != nil {
+		addApiError(&resp.Diagnostics, err)
+		return
+	}
+
+	// Ids are assigned incrementally, the last vmi is the newest one whatever
+	// its name.
+	sort.SliceStable(vmis, func(i, j int) bool {
+		return idLess(vmis[i].Id, vmis[j].Id)
+	})
+
+	data.Vmis = []vmisVmiModel{}
+	for _, vmi := range vmis {
+		if !nameRegex.MatchString(types.StringPointerValue(vmi.Name).ValueString()) {
+			continue
+		}
+
+		if !data.Status.IsNull() && types.StringPointerValue((*string)(vmi.Status)).ValueString() != data.Status.ValueString() {
+			continue
+		}
+
+		data.Vmis = append(data.Vmis, vmisVmiModel{
+			Id:     idPointerValue(vmi.Id),
+			Name:   types.StringPointerValue(vmi.Name),
+			Status: types.StringPointerValue((*string)(vmi.Status)),
+		})
+	}
+
+	tflog.Trace(ctx, "read vmis data source", map[string]interface{}{"count": len(data.Vmis)})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,45 @@
+package provider_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccVmisDataSource(t *testing.T) {
+	server, url := acctest.NewServer(t)
+	// Registered out of name order, the list follows the ids.
+	noble := server.AddVmi("ubuntu-noble")
+	jammy := server.AddVmi("ubuntu-jammy")
+	server.AddVmi("debian-bookworm")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(url) + `
+data "crunchloop_vmis" "test" {
+  name_regex = "^ubuntu-"
+  status     = "available"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.crunchloop_vmis.test", "vmis.#", "2"),
+					resource.TestCheckResourceAttr("data.crunchloop_vmis.test", "vmis.0.id", fmt.Sprint(*noble.Id)),
+					resource.TestCheckResourceAttr("data.crunchloop_vmis.test", "vmis.1.id", fmt.Sprint(*jammy.Id)),
+					resource.TestCheckResourceAttr("data.crunchloop_vmis.test", "vmis.1.name", "ubuntu-jammy"),
+				),
+			},
+			{
+				Config: acctest.ProviderConfig(url) + `
+data "crunchloop_vmis" "test" {
+  status = "failed"
+}
+`,
+				Check: resource.TestCheckResourceAttr("data.crunchloop_vmis.test", "vmis.#", "0"),
+			},
+		},
+	})
+}
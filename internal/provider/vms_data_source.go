@@ -0,0 +1,149 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/services"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/tracing"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &VmsDataSource{}
+
+func NewVmsDataSource() datasource.DataSource {
+	return &VmsDataSource{}
+}
+
+// VmsDataSource defines the data source implementation.
+type VmsDataSource struct {
+	service *services.VmService
+}
+
+// VmsDataSourceModel describes the data source data model.
+type VmsDataSourceModel struct {
+	NameRegex types.String        `tfsdk:"name_regex"`
+	Status    types.String        `tfsdk:"status"`
+	Vms       []VmDataSourceModel `tfsdk:"vms"`
+}
+
+func (d *VmsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vms"
+}
+
+func (d *VmsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Vms data source, lists every Vm matching the given filters. There is no object type filter, the API only lists vms here",
+
+		Attributes: map[string]schema.Attribute{
+			"name_regex": schema.StringAttribute{
+				MarkdownDescription: "Regular expression the Vm name must match",
+				Optional:            true,
+				Validators: []validator.String{
+					regexpValidator{},
+				},
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "Only return Vms with this status, e.g. `running`, `stopped` or `suspended`",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						string(client.VirtualMachineStatusCreating),
+						string(client.VirtualMachineStatusRunning),
+						string(client.VirtualMachineStatusStopped),
+						string(client.VirtualMachineStatusSuspended),
+						string(client.VirtualMachineStatusUpdating),
+						string(client.VirtualMachineStatusDeleting),
+					),
+				},
+			},
+			"vms": schema.ListNestedAttribute{
+				MarkdownDescription: "Matching Vms, sorted by id, i.e. in creation order",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: vmDataSourceAttributes(),
+				},
+			},
+		},
+	}
+}
+
+func (d *VmsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*CrunchloopProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *CrunchloopProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.service = services.NewVmService(providerData.Client)
+}
+
+func (d *VmsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, span := tracing.Start(ctx, "data.crunchloop_vms", "Read")
+	defer tracing.End(span, &resp.Diagnostics)
+
+	var data VmsDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	nameRegex, err := regexp.Compile(data.NameRegex.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Regular Expression", err.Error())
+		return
+	}
+
+	vms, err := d.service.ListVms(ctx)
+	if err != nil {
+		addApiError(&resp.Diagnostics, err)
+		return
+	}
+
+	// Sorted by id like the other list data sources, see HostsDataSource.
+	sort.SliceStable(vms, func(i, j int) bool {
+		return idLess(vms[i].Id, vms[j].Id)
+	})
+
+	data.Vms = []VmDataSourceModel{}
+	for i := range vms {
+		if !nameRegex.MatchString(types.StringPointerValue(vms[i].Name).ValueString()) {
+			continue
+		}
+
+		if !data.Status.IsNull() && types.StringPointerValue((*string)(vms[i].Status)).ValueString() != data.Status.ValueString() {
+			continue
+		}
+
+		var vm VmDataSourceModel
+		vm.vmModelToState(&vms[i])
+		data.Vms = append(data.Vms, vm)
+	}
+
+	tflog.Trace(ctx, "read vms data source", map[string]interface{}{"count": len(data.Vms)})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,70 @@
+package provider_test
+
+import (
+	"testing"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/acctest"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccVmsDataSource(t *testing.T) {
+	server, url := acctest.NewServer(t)
+	server.AddHost("host-1", client.Online)
+	vmi := server.AddVmi("ubuntu-jammy")
+
+	// Created out of name order, the list follows the ids.
+	config := acctest.ProviderConfig(url) + testAccVmsConfig(*vmi.Id, "web-2", "db-1", "web-1")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				Config: config + `
+data "crunchloop_vms" "test" {
+  name_regex = "^web-"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.crunchloop_vms.test", "vms.#", "2"),
+					resource.TestCheckResourceAttrPair("data.crunchloop_vms.test", "vms.0.id", "crunchloop_vm.test_0", "id"),
+					resource.TestCheckResourceAttr("data.crunchloop_vms.test", "vms.0.name", "web-2"),
+					resource.TestCheckResourceAttrPair("data.crunchloop_vms.test", "vms.1.id", "crunchloop_vm.test_2", "id"),
+					resource.TestCheckResourceAttr("data.crunchloop_vms.test", "vms.1.name", "web-1"),
+					resource.TestCheckResourceAttr("data.crunchloop_vms.test", "vms.1.status", "running"),
+				),
+			},
+			{
+				PreConfig: func() {
+					// The vms are created in order after the host and the
+					// vmi, each along with its root volume and nic, db-1 is
+					// the second one.
+					if !server.SetVmStatus(*vmi.Id+4, client.VirtualMachineStatusStopped) {
+						t.Fatal("vm not found")
+					}
+				},
+				Config: config + `
+data "crunchloop_vms" "test" {
+  status = "stopped"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.crunchloop_vms.test", "vms.#", "1"),
+					resource.TestCheckResourceAttrPair("data.crunchloop_vms.test", "vms.0.id", "crunchloop_vm.test_1", "id"),
+					resource.TestCheckResourceAttr("data.crunchloop_vms.test", "vms.0.name", "db-1"),
+				),
+			},
+			{
+				Config: config + `
+data "crunchloop_vms" "test" {
+  name_regex = "^cache-"
+}
+`,
+				Check: resource.TestCheckResourceAttr("data.crunchloop_vms.test", "vms.#", "0"),
+			},
+		},
+	})
+}
@@ -0,0 +1,15 @@
+package services
+
+import (
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
+)
+
+// Api is the Crunchloop API the services are programmed against. It's
+// satisfied by the generated client, and by anything wrapping it (caching,
+// metrics, ...) or faking it in tests.
+type Api interface {
+	client.ClientWithResponsesInterface
+}
+
+// Ensure the generated client satisfies Api.
+var _ Api = &client.ClientWithResponses{}
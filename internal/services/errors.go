@@ -0,0 +1,106 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
+)
+
+// ApiError describes an unexpected response from the Crunchloop API. Code,
+// Message and Details are decoded from the Error schema when the response
+// body contains one.
+type ApiError struct {
+	Operation  string
+	StatusCode int
+	Code       client.ErrorCode
+	Message    string
+	Details    []client.ErrorDetail
+	RequestId  string
+}
+
+func (e *ApiError) Error() string {
+	message := e.Message
+	if message == "" {
+		message = fmt.Sprintf("unexpected status code %d", e.StatusCode)
+	}
+
+	if e.RequestId != "" {
+		return fmt.Sprintf("failed to %s: %s (request id: %s)", e.Operation, message, e.RequestId)
+	}
+
+	return fmt.Sprintf("failed to %s: %s", e.Operation, message)
+}
+
+// IsNotFound reports whether err is an ApiError for a record that doesn't
+// exist, usually because it was deleted outside of Terraform. Only a decoded
+// record_not_found error qualifies, a bare 404, e.g. from a wrong url or a
+// proxy, must not remove resources from state.
+func IsNotFound(err error) bool {
+	var apiErr *ApiError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	return apiErr.Code == client.RecordNotFound
+}
+
+// IsInputError reports whether err is an ApiError caused by invalid input.
+func IsInputError(err error) bool {
+	var apiErr *ApiError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	return apiErr.Code == client.InputError
+}
+
+// IsUnauthorized reports whether err is an ApiError caused by the API
+// rejecting the request credentials.
+func IsUnauthorized(err error) bool {
+	var apiErr *ApiError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	return apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden
+}
+
+// notFoundError builds the error returned when a record looked up through a
+// list endpoint isn't part of it, so it is reported like a record_not_found
+// response.
+func notFoundError(operation string, message string) error {
+	return &ApiError{
+		Operation:  operation,
+		StatusCode: http.StatusNotFound,
+		Code:       client.RecordNotFound,
+		Message:    message,
+	}
+}
+
+// responseError builds the error returned when the API answers with an
+// unexpected status code.
+func responseError(operation string, response *http.Response, body []byte) error {
+	apiErr := &ApiError{
+		Operation: operation,
+	}
+
+	if response != nil {
+		apiErr.StatusCode = response.StatusCode
+		apiErr.RequestId = response.Header.Get("X-Request-Id")
+	}
+
+	var payload client.Error
+	if err := json.Unmarshal(body, &payload); err == nil {
+		apiErr.Code = payload.Code
+		apiErr.Message = payload.Message
+
+		if payload.Details != nil {
+			apiErr.Details = *payload.Details
+		}
+	}
+
+	return apiErr
+}
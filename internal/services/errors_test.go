@@ -0,0 +1,150 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestIsNotFound(t *testing.T) {
+	cases := []struct {
+		name       string
+		statusCode int
+		body       string
+		expected   bool
+	}{
+		{
+			name:       "record not found",
+			statusCode: http.StatusNotFound,
+			body:       `{"code":"record_not_found","message":"Vm not found"}`,
+			expected:   true,
+		},
+		{
+			name:       "html not found",
+			statusCode: http.StatusNotFound,
+			body:       `<html><body>404 Not Found</body></html>`,
+			expected:   false,
+		},
+		{
+			name:       "empty not found",
+			statusCode: http.StatusNotFound,
+			body:       ``,
+			expected:   false,
+		},
+		{
+			name:       "input error",
+			statusCode: http.StatusUnprocessableEntity,
+			body:       `{"code":"input_error","message":"Name is invalid"}`,
+			expected:   false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := responseError("get vm", &http.Response{StatusCode: c.statusCode, Header: http.Header{}}, []byte(c.body))
+
+			if actual := IsNotFound(err); actual != c.expected {
+				t.Errorf("expected IsNotFound to be %t, got %t", c.expected, actual)
+			}
+
+			if actual := IsNotFound(fmt.Errorf("wrapped: %w", err)); actual != c.expected {
+				t.Errorf("expected IsNotFound on a wrapped error to be %t, got %t", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestIsInputError(t *testing.T) {
+	cases := []struct {
+		name       string
+		statusCode int
+		body       string
+		expected   bool
+	}{
+		{
+			name:       "input error",
+			statusCode: http.StatusBadRequest,
+			body:       `{"code":"input_error","message":"name can't be blank","details":[{"field":"name","message":"can't be blank"}]}`,
+			expected:   true,
+		},
+		{
+			name:       "input error without details",
+			statusCode: http.StatusUnprocessableEntity,
+			body:       `{"code":"input_error","message":"Name is invalid"}`,
+			expected:   true,
+		},
+		{
+			name:       "bare bad request",
+			statusCode: http.StatusBadRequest,
+			body:       `<html><body>400 Bad Request</body></html>`,
+			expected:   false,
+		},
+		{
+			name:       "record not found",
+			statusCode: http.StatusNotFound,
+			body:       `{"code":"record_not_found","message":"Vm not found"}`,
+			expected:   false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := responseError("create vm", &http.Response{StatusCode: c.statusCode, Header: http.Header{}}, []byte(c.body))
+
+			if actual := IsInputError(err); actual != c.expected {
+				t.Errorf("expected IsInputError to be %t, got %t", c.expected, actual)
+			}
+
+			if actual := IsInputError(fmt.Errorf("wrapped: %w", err)); actual != c.expected {
+				t.Errorf("expected IsInputError on a wrapped error to be %t, got %t", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestIsUnauthorized(t *testing.T) {
+	cases := []struct {
+		name       string
+		statusCode int
+		body       string
+		expected   bool
+	}{
+		{
+			name:       "unauthorized",
+			statusCode: http.StatusUnauthorized,
+			body:       `missing api token`,
+			expected:   true,
+		},
+		{
+			name:       "forbidden",
+			statusCode: http.StatusForbidden,
+			body:       `{"code":"input_error","message":"Invalid api token"}`,
+			expected:   true,
+		},
+		{
+			name:       "input error",
+			statusCode: http.StatusBadRequest,
+			body:       `{"code":"input_error","message":"Name is invalid"}`,
+			expected:   false,
+		},
+		{
+			name:       "internal server error",
+			statusCode: http.StatusInternalServerError,
+			expected:   false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := responseError("list hosts", &http.Response{StatusCode: c.statusCode, Header: http.Header{}}, []byte(c.body))
+
+			if actual := IsUnauthorized(err); actual != c.expected {
+				t.Errorf("expected IsUnauthorized to be %t, got %t", c.expected, actual)
+			}
+
+			if actual := IsUnauthorized(fmt.Errorf("wrapped: %w", err)); actual != c.expected {
+				t.Errorf("expected IsUnauthorized on a wrapped error to be %t, got %t", c.expected, actual)
+			}
+		})
+	}
+}
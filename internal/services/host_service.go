@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
+)
+
+type HostService struct {
+	client Api
+}
+
+func NewHostService(client Api) *HostService {
+	return &HostService{
+		client: client,
+	}
+}
+
+// IterateHosts returns an iterator over every host, following the
+// pagination cursor until the last page.
+func (s *HostService) IterateHosts() *Iterator[client.Host] {
+	return NewIterator(func(ctx context.Context, limit int32, startingAfter *int32) ([]client.Host, bool, error) {
+		response, err := s.client.ListHostsWithResponse(ctx, &client.ListHostsParams{
+			Limit:         &limit,
+			StartingAfter: startingAfter,
+		})
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to list hosts: %w", err)
+		}
+
+		if response.StatusCode() != 200 {
+			return nil, false, responseError("list hosts", response.HTTPResponse, response.Body)
+		}
+
+		var page []client.Host
+		if response.JSON200.Data != nil {
+			page = *response.JSON200.Data
+		}
+
+		return page, response.JSON200.HasMore != nil && *response.JSON200.HasMore, nil
+	}, func(host client.Host) *int32 {
+		return host.Id
+	})
+}
+
+// ListHosts returns every host, across all pages.
+func (s *HostService) ListHosts(ctx context.Context) ([]client.Host, error) {
+	return s.IterateHosts().All(ctx)
+}
+
+func (s *HostService) CreateProxmoxHost(ctx context.Context, options client.CreateProxmoxHostJSONRequestBody) (*client.Host, error) {
+	createResponse, err := s.client.CreateProxmoxHostWithResponse(ctx, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create host: %w", err)
+	}
+
+	if createResponse.StatusCode() != 201 {
+		return nil, responseError("create host", createResponse.HTTPResponse, createResponse.Body)
+	}
+
+	return createResponse.JSON201, nil
+}
+
+// GetHost returns the host with the given id. The API has no endpoint to get
+// a single host, so it is looked up through the hosts list, a host missing
+// from it is reported as not found.
+func (s *HostService) GetHost(ctx context.Context, id int32) (*client.Host, error) {
+	iterator := s.IterateHosts()
+	for iterator.Next(ctx) {
+		if host := iterator.Value(); host.Id != nil && *host.Id == id {
+			return &host, nil
+		}
+	}
+
+	if err := iterator.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, notFoundError("get host", fmt.Sprintf("host %d not found", id))
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"errors"
+)
+
+// pageSize is the number of objects requested on every page.
+const pageSize int32 = 100
+
+// PageFetcher fetches the page of objects that comes after the given cursor,
+// a nil cursor fetches the first page.
+type PageFetcher[T any] func(ctx context.Context, limit int32, startingAfter *int32) (page []T, hasMore bool, err error)
+
+// Iterator walks through every object of a paginated list endpoint, fetching
+// the next page only when the current one is exhausted.
+//
+//	iterator := service.IterateHosts()
+//	for iterator.Next(ctx) {
+//		host := iterator.Value()
+//	}
+//	if err := iterator.Err(); err != nil {
+//		...
+//	}
+type Iterator[T any] struct {
+	fetch   PageFetcher[T]
+	id      func(T) *int32
+	page    []T
+	index   int
+	cursor  *int32
+	hasMore bool
+	started bool
+	err     error
+}
+
+// NewIterator returns an iterator over the pages returned by fetch, id must
+// return the identifier used as cursor for the following page.
+func NewIterator[T any](fetch PageFetcher[T], id func(T) *int32) *Iterator[T] {
+	return &Iterator[T]{
+		fetch: fetch,
+		id:    id,
+		index: -1,
+	}
+}
+
+// Next advances the iterator to the next object, it returns false once every
+// object was visited or when a page failed to be fetched.
+func (i *Iterator[T]) Next(ctx context.Context) bool {
+	if i.err != nil {
+		return false
+	}
+
+	i.index++
+	for i.index >= len(i.page) {
+		if i.started && !i.hasMore {
+			return false
+		}
+
+		if i.started {
+			// Guard against servers reporting more pages without returning
+			// objects, the cursor wouldn't move and we would loop forever.
+			// Stopping silently would truncate the list, lookups would then
+			// report existing objects as not found.
+			if len(i.page) == 0 {
+				i.err = errors.New("failed to paginate: empty page reported more objects")
+				return false
+			}
+
+			i.cursor = i.id(i.page[len(i.page)-1])
+			if i.cursor == nil {
+				i.err = errors.New("failed to paginate: last object of the page has no id")
+				return false
+			}
+		}
+
+		i.page, i.hasMore, i.err = i.fetch(ctx, pageSize, i.cursor)
+		i.index = 0
+		i.started = true
+
+		if i.err != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Value returns the current object.
+func (i *Iterator[T]) Value() T {
+	return i.page[i.index]
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (i *Iterator[T]) Err() error {
+	return i.err
+}
+
+// All drains the iterator and returns every remaining object.
+func (i *Iterator[T]) All(ctx context.Context) ([]T, error) {
+	result := []T{}
+	for i.Next(ctx) {
+		result = append(result, i.Value())
+	}
+
+	if err := i.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
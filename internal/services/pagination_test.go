@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type testObject struct {
+	Id *int32
+}
+
+func testObjects(ids ...int32) []testObject {
+	objects := make([]testObject, 0, len(ids))
+	for i := range ids {
+		objects = append(objects, testObject{Id: &ids[i]})
+	}
+
+	return objects
+}
+
+func testObjectId(object testObject) *int32 {
+	return object.Id
+}
+
+// testPages returns a PageFetcher serving the given pages in order, recording
+// the cursors it was called with.
+func testPages(cursors *[]*int32, pages ...[]testObject) PageFetcher[testObject] {
+	return func(ctx context.Context, limit int32, startingAfter *int32) ([]testObject, bool, error) {
+		index := len(*cursors)
+		*cursors = append(*cursors, startingAfter)
+
+		return pages[index], index < len(pages)-1, nil
+	}
+}
+
+func ids(objects []testObject) []int32 {
+	result := make([]int32, 0, len(objects))
+	for _, object := range objects {
+		result = append(result, *object.Id)
+	}
+
+	return result
+}
+
+func TestIterator_multiplePages(t *testing.T) {
+	var cursors []*int32
+	iterator := NewIterator(testPages(&cursors, testObjects(1, 2), testObjects(3, 4), testObjects(5)), testObjectId)
+
+	objects, err := iterator.All(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if got := ids(objects); !reflect.DeepEqual(got, []int32{1, 2, 3, 4, 5}) {
+		t.Errorf("unexpected objects %v", got)
+	}
+
+	if len(cursors) != 3 || cursors[0] != nil || *cursors[1] != 2 || *cursors[2] != 4 {
+		t.Errorf("unexpected cursors %v", cursors)
+	}
+}
+
+func TestIterator_emptyList(t *testing.T) {
+	var cursors []*int32
+	iterator := NewIterator(testPages(&cursors, testObjects()), testObjectId)
+
+	objects, err := iterator.All(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(objects) != 0 {
+		t.Errorf("expected no objects, got %v", ids(objects))
+	}
+}
+
+func TestIterator_stopsEarly(t *testing.T) {
+	var cursors []*int32
+	iterator := NewIterator(testPages(&cursors, testObjects(1, 2), testObjects(3)), testObjectId)
+
+	if !iterator.Next(context.Background()) || *iterator.Value().Id != 1 {
+		t.Fatal("expected the first object")
+	}
+
+	if len(cursors) != 1 {
+		t.Errorf("expected a single page to be fetched, got %d", len(cursors))
+	}
+}
+
+func TestIterator_emptyPageWithMore(t *testing.T) {
+	var cursors []*int32
+	iterator := NewIterator(testPages(&cursors, testObjects(1), testObjects(), testObjects(2)), testObjectId)
+
+	if _, err := iterator.All(context.Background()); err == nil {
+		t.Fatal("expected an error, the list would be truncated")
+	}
+
+	if len(cursors) != 2 {
+		t.Errorf("expected the iteration to stop after the empty page, got %d pages", len(cursors))
+	}
+}
+
+func TestIterator_nilId(t *testing.T) {
+	var cursors []*int32
+	iterator := NewIterator(testPages(&cursors, []testObject{{}}, testObjects(2)), testObjectId)
+
+	if _, err := iterator.All(context.Background()); err == nil {
+		t.Fatal("expected an error, the next page can't be requested")
+	}
+}
+
+func TestIterator_fetchError(t *testing.T) {
+	fetchErr := errors.New("failed to list")
+	iterator := NewIterator(func(ctx context.Context, limit int32, startingAfter *int32) ([]testObject, bool, error) {
+		return nil, false, fetchErr
+	}, testObjectId)
+
+	if iterator.Next(context.Background()) {
+		t.Fatal("expected no objects")
+	}
+
+	if !errors.Is(iterator.Err(), fetchErr) {
+		t.Errorf("expected the fetch error, got %v", iterator.Err())
+	}
+}
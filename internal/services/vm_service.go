@@ -9,33 +9,85 @@ import (
 )
 
 type VmService struct {
-	client *client.ClientWithResponses
+	client Api
 }
 
-func NewVmService(client *client.ClientWithResponses) *VmService {
+func NewVmService(client Api) *VmService {
 	return &VmService{
 		client: client,
 	}
 }
 
+// IterateVms returns an iterator over every vm, following the
+// pagination cursor until the last page.
+func (s *VmService) IterateVms() *Iterator[client.VirtualMachine] {
+	return NewIterator(func(ctx context.Context, limit int32, startingAfter *int32) ([]client.VirtualMachine, bool, error) {
+		response, err := s.client.ListVmsWithResponse(ctx, &client.ListVmsParams{
+			Limit:         &limit,
+			StartingAfter: startingAfter,
+		})
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to list vms: %w", err)
+		}
+
+		if response.StatusCode() != 200 {
+			return nil, false, responseError("list vms", response.HTTPResponse, response.Body)
+		}
+
+		var page []client.VirtualMachine
+		if response.JSON200.Data != nil {
+			page = *response.JSON200.Data
+		}
+
+		return page, response.JSON200.HasMore != nil && *response.JSON200.HasMore, nil
+	}, func(vm client.VirtualMachine) *int32 {
+		return vm.Id
+	})
+}
+
+// ListVms returns every vm, across all pages.
+func (s *VmService) ListVms(ctx context.Context) ([]client.VirtualMachine, error) {
+	return s.IterateVms().All(ctx)
+}
+
+// CreateVm creates a vm and waits until it's running. When the wait fails the
+// created vm is returned with the error.
 func (s *VmService) CreateVm(ctx context.Context, options client.CreateVmJSONRequestBody) (*client.VirtualMachine, error) {
 	createResponse, err := s.client.CreateVmWithResponse(ctx, options)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create. Error: %s", err)
+		return nil, fmt.Errorf("failed to create vm: %w", err)
 	}
 
 	if createResponse.StatusCode() != 201 {
-		return nil, fmt.Errorf("failed to create vm. Response body: %s", createResponse.Body)
+		return nil, responseError("create vm", createResponse.HTTPResponse, createResponse.Body)
+	}
+
+	if createResponse.JSON201 == nil || createResponse.JSON201.Id == nil {
+		return nil, fmt.Errorf("failed to create vm: missing id")
 	}
 
-	err = utils.WaitForVmStatus(ctx, s.client, *createResponse.JSON201.Id, "running")
+	// The vm exists from now on, it's returned along with any error so
+	// callers can keep track of it instead of leaking it.
+	id := *createResponse.JSON201.Id
+	err = utils.WaitForVmStatus(ctx, vmRefreshFunc(s.client, id), id, "running")
 	if err != nil {
-		return nil, err
+		return createResponse.JSON201, fmt.Errorf("failed while waiting for vm to be running: %w", err)
 	}
 
-	vm, err := s.GetVm(ctx, *createResponse.JSON201.Id)
+	vm, err := s.GetVm(ctx, id)
 	if err != nil {
-		return nil, err
+		return createResponse.JSON201, err
+	}
+
+	return vm, nil
+}
+
+// WaitForVmIpAddress waits until an IP address is assigned to the vm network
+// interface, e.g. when it's leased through DHCP after boot.
+func (s *VmService) WaitForVmIpAddress(ctx context.Context, id int32) (*client.VirtualMachine, error) {
+	vm, err := utils.WaitForVmIpAddress(ctx, vmRefreshFunc(s.client, id), id)
+	if err != nil {
+		return nil, fmt.Errorf("failed while waiting for vm ip address: %w", err)
 	}
 
 	return vm, nil
@@ -44,11 +96,11 @@ func (s *VmService) CreateVm(ctx context.Context, options client.CreateVmJSONReq
 func (s *VmService) GetVm(ctx context.Context, id int32) (*client.VirtualMachine, error) {
 	response, err := s.client.GetVmWithResponse(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create. Error: %s", err)
+		return nil, fmt.Errorf("failed to get vm: %w", err)
 	}
 
 	if response.StatusCode() != 200 {
-		return nil, fmt.Errorf("failed to get vm. Response body: %s", response.Body)
+		return nil, responseError("get vm", response.HTTPResponse, response.Body)
 	}
 
 	return response.JSON200, nil
@@ -57,11 +109,18 @@ func (s *VmService) GetVm(ctx context.Context, id int32) (*client.VirtualMachine
 func (s *VmService) DeleteVm(ctx context.Context, id int32) error {
 	response, err := s.client.DeleteVmWithResponse(ctx, id)
 	if err != nil {
-		return fmt.Errorf("failed to delete. Error: %s", err)
+		return fmt.Errorf("failed to delete vm: %w", err)
 	}
 
 	if response.StatusCode() != 204 {
-		return fmt.Errorf("failed to delete vm. Response body: %s", response.Body)
+		return responseError("delete vm", response.HTTPResponse, response.Body)
+	}
+
+	// The API answers as soon as the deletion is scheduled, wait until the vm
+	// is actually gone so its name and host capacity can be reused right away.
+	err = utils.WaitForVmDeletion(ctx, vmRefreshFunc(s.client, id), id)
+	if err != nil {
+		return fmt.Errorf("failed while waiting for vm to be deleted, it may be stuck: %w", err)
 	}
 
 	return nil
@@ -76,17 +135,17 @@ func (s *VmService) UpdateVm(ctx context.Context, id int32, options client.Updat
 	// We are ready to update the vm now.
 	updateResponse, err := s.client.UpdateVmWithResponse(ctx, id, options)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update. Error: %s", err)
+		return nil, fmt.Errorf("failed to update vm: %w", err)
 	}
 
 	if updateResponse.StatusCode() != 200 {
-		return nil, fmt.Errorf("failed to update vm. Response body: %s", updateResponse.Body)
+		return nil, responseError("update vm", updateResponse.HTTPResponse, updateResponse.Body)
 	}
 
 	// After we issue an update, the vm is going to transition to `updating` state
 	// and eventually will be back to `currentStatus` state, we need to wait for that
 	// state before moving forward.
-	err = utils.WaitForVmStatus(ctx, s.client, *vm.Id, *vm.Status)
+	err = utils.WaitForVmStatus(ctx, vmRefreshFunc(s.client, *vm.Id), *vm.Id, *vm.Status)
 	if err != nil {
 		return nil, err
 	}
@@ -100,14 +159,18 @@ func (s *VmService) UpdateVm(ctx context.Context, id int32, options client.Updat
 }
 
 func (s *VmService) StopVm(ctx context.Context, id int32) (*client.VirtualMachine, error) {
-	_, err := s.client.StopVmWithResponse(ctx, id)
+	response, err := s.client.StopVmWithResponse(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to stop vm: %s", err)
+		return nil, fmt.Errorf("failed to stop vm: %w", err)
+	}
+
+	if response.StatusCode() != 200 {
+		return nil, responseError("stop vm", response.HTTPResponse, response.Body)
 	}
 
-	err = utils.WaitForVmStatus(ctx, s.client, id, "stopped")
+	err = utils.WaitForVmStatus(ctx, vmRefreshFunc(s.client, id), id, "stopped")
 	if err != nil {
-		return nil, fmt.Errorf("failed while waiting for vm to be stopped: %s", err)
+		return nil, fmt.Errorf("failed while waiting for vm to be stopped: %w", err)
 	}
 
 	vm, err := s.GetVm(ctx, id)
@@ -119,14 +182,45 @@ func (s *VmService) StopVm(ctx context.Context, id int32) (*client.VirtualMachin
 }
 
 func (s *VmService) StartVm(ctx context.Context, id int32) (*client.VirtualMachine, error) {
-	_, err := s.client.StartVmWithResponse(ctx, id)
+	response, err := s.client.StartVmWithResponse(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to start vm: %s", err)
+		return nil, fmt.Errorf("failed to start vm: %w", err)
 	}
 
-	err = utils.WaitForVmStatus(ctx, s.client, id, "running")
+	if response.StatusCode() != 200 {
+		return nil, responseError("start vm", response.HTTPResponse, response.Body)
+	}
+
+	err = utils.WaitForVmStatus(ctx, vmRefreshFunc(s.client, id), id, "running")
+	if err != nil {
+		return nil, fmt.Errorf("failed while waiting for vm to be running: %w", err)
+	}
+
+	vm, err := s.GetVm(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return vm, nil
+}
+
+func (s *VmService) RebootVm(ctx context.Context, id int32) (*client.VirtualMachine, error) {
+	response, err := s.client.RebootVmWithResponse(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reboot vm: %w", err)
+	}
+
+	if response.StatusCode() != 200 {
+		return nil, responseError("reboot vm", response.HTTPResponse, response.Body)
+	}
+
+	// The reboot may already be reported by the response, otherwise the vm
+	// may keep reporting running while it reboots, see WaitForVmReboot.
+	rebooting := response.JSON200 != nil && response.JSON200.Status != nil && *response.JSON200.Status != client.VirtualMachineStatusRunning
+
+	err = utils.WaitForVmReboot(ctx, vmRefreshFunc(s.client, id), id, rebooting)
 	if err != nil {
-		return nil, fmt.Errorf("failed while waiting for vm to be running: %s", err)
+		return nil, fmt.Errorf("failed while waiting for vm to be rebooted: %w", err)
 	}
 
 	vm, err := s.GetVm(ctx, id)
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/utils"
+)
+
+type VmiService struct {
+	client Api
+}
+
+func NewVmiService(client Api) *VmiService {
+	return &VmiService{
+		client: client,
+	}
+}
+
+// IterateVmis returns an iterator over every vmi, following the
+// pagination cursor until the last page.
+func (s *VmiService) IterateVmis() *Iterator[client.VirtualMachineImage] {
+	return NewIterator(func(ctx context.Context, limit int32, startingAfter *int32) ([]client.VirtualMachineImage, bool, error) {
+		response, err := s.client.ListVmisWithResponse(ctx, &client.ListVmisParams{
+			Limit:         &limit,
+			StartingAfter: startingAfter,
+		})
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to list vmis: %w", err)
+		}
+
+		if response.StatusCode() != 200 {
+			return nil, false, responseError("list vmis", response.HTTPResponse, response.Body)
+		}
+
+		var page []client.VirtualMachineImage
+		if response.JSON200.Data != nil {
+			page = *response.JSON200.Data
+		}
+
+		return page, response.JSON200.HasMore != nil && *response.JSON200.HasMore, nil
+	}, func(vmi client.VirtualMachineImage) *int32 {
+		return vmi.Id
+	})
+}
+
+// ListVmis returns every vmi, across all pages.
+func (s *VmiService) ListVmis(ctx context.Context) ([]client.VirtualMachineImage, error) {
+	return s.IterateVmis().All(ctx)
+}
+
+// CreateProxmoxVmi registers a vmi and waits until it's available. When the
+// wait fails the registered vmi is returned with the error.
+func (s *VmiService) CreateProxmoxVmi(ctx context.Context, options client.CreateProxmoxVmiJSONRequestBody) (*client.VirtualMachineImage, error) {
+	createResponse, err := s.client.CreateProxmoxVmiWithResponse(ctx, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create vmi: %w", err)
+	}
+
+	if createResponse.StatusCode() != 201 {
+		return nil, responseError("create vmi", createResponse.HTTPResponse, createResponse.Body)
+	}
+
+	// The image is downloaded and verified asynchronously, it can't be used
+	// by a vm until it becomes available. The vmi exists from now on, it's
+	// returned along with any error so callers can keep track of it.
+	id := *createResponse.JSON201.Id
+	err = utils.WaitForVmiStatus(ctx, vmiRefreshFunc(s, id), id, client.VirtualMachineImageStatusAvailable)
+	if err != nil {
+		return createResponse.JSON201, fmt.Errorf("failed while waiting for vmi to be available: %w", err)
+	}
+
+	vmi, err := s.GetVmi(ctx, id)
+	if err != nil {
+		return createResponse.JSON201, err
+	}
+
+	return vmi, nil
+}
+
+// GetVmi returns the vmi with the given id. The API has no endpoint to get a
+// single vmi, so it is looked up through the vmis list, a vmi missing from it
+// is reported as not found.
+func (s *VmiService) GetVmi(ctx context.Context, id int32) (*client.VirtualMachineImage, error) {
+	iterator := s.IterateVmis()
+	for iterator.Next(ctx) {
+		if vmi := iterator.Value(); vmi.Id != nil && *vmi.Id == id {
+			return &vmi, nil
+		}
+	}
+
+	if err := iterator.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, notFoundError("get vmi", fmt.Sprintf("vmi %d not found", id))
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/utils"
+)
+
+// vmRefreshFunc returns a utils.StateRefreshFunc reporting the vm status.
+func vmRefreshFunc(api Api, id int32) utils.StateRefreshFunc {
+	return func(ctx context.Context) (interface{}, string, error) {
+		response, err := api.GetVmWithResponse(ctx, id)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get vm: %w", err)
+		}
+
+		if response.StatusCode() != 200 {
+			err := responseError("get vm", response.HTTPResponse, response.Body)
+			if IsNotFound(err) {
+				return nil, utils.NotFoundState, nil
+			}
+
+			return nil, "", refreshError(err)
+		}
+
+		if response.JSON200 == nil || response.JSON200.Status == nil {
+			return nil, "", fmt.Errorf("failed to get vm: missing status")
+		}
+
+		return response.JSON200, string(*response.JSON200.Status), nil
+	}
+}
+
+// vmiRefreshFunc returns a utils.StateRefreshFunc reporting the vmi status.
+// A vmi without a status is reported as available, the status is optional in
+// the API responses and such a vmi can't be waited on.
+func vmiRefreshFunc(service *VmiService, id int32) utils.StateRefreshFunc {
+	return func(ctx context.Context) (interface{}, string, error) {
+		vmi, err := service.GetVmi(ctx, id)
+		if err != nil {
+			if IsNotFound(err) {
+				return nil, utils.NotFoundState, nil
+			}
+
+			return nil, "", refreshError(err)
+		}
+
+		if vmi.Status == nil {
+			return vmi, string(client.VirtualMachineImageStatusAvailable), nil
+		}
+
+		return vmi, string(*vmi.Status), nil
+	}
+}
+
+// refreshError classifies an unexpected response while waiting. Client errors
+// other than rate limiting, e.g. rejected credentials, won't go away by
+// polling again so they abort the wait, anything else is retried as transient.
+func refreshError(err error) error {
+	var apiErr *ApiError
+	if errors.As(err, &apiErr) && apiErr.StatusCode >= 400 && apiErr.StatusCode < 500 && apiErr.StatusCode != http.StatusTooManyRequests {
+		return &utils.PermanentError{Err: err}
+	}
+
+	return err
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/utils"
+)
+
+// fakeGetVmApi answers GetVm with the given status code and body.
+type fakeGetVmApi struct {
+	Api
+
+	statusCode int
+	body       string
+}
+
+func (a *fakeGetVmApi) GetVmWithResponse(ctx context.Context, id int32, reqEditors ...client.RequestEditorFn) (*client.GetVmResponse, error) {
+	return &client.GetVmResponse{
+		Body:         []byte(a.body),
+		HTTPResponse: &http.Response{StatusCode: a.statusCode, Header: http.Header{}},
+	}, nil
+}
+
+func TestVmRefreshFunc_errors(t *testing.T) {
+	cases := []struct {
+		name       string
+		statusCode int
+		body       string
+		state      string
+		permanent  bool
+	}{
+		{
+			name:       "record not found",
+			statusCode: http.StatusNotFound,
+			body:       `{"code":"record_not_found","message":"Vm not found"}`,
+			state:      utils.NotFoundState,
+		},
+		{
+			name:       "bare not found",
+			statusCode: http.StatusNotFound,
+			body:       `<html><body>404 Not Found</body></html>`,
+			permanent:  true,
+		},
+		{
+			name:       "unauthorized",
+			statusCode: http.StatusUnauthorized,
+			body:       `{"code":"input_error","message":"Invalid api token"}`,
+			permanent:  true,
+		},
+		{
+			name:       "forbidden",
+			statusCode: http.StatusForbidden,
+			permanent:  true,
+		},
+		{
+			name:       "too many requests",
+			statusCode: http.StatusTooManyRequests,
+		},
+		{
+			name:       "service unavailable",
+			statusCode: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			refresh := vmRefreshFunc(&fakeGetVmApi{statusCode: c.statusCode, body: c.body}, 1)
+
+			_, state, err := refresh(context.Background())
+			if state != c.state {
+				t.Errorf("expected state %q, got %q", c.state, state)
+			}
+
+			if c.state != "" {
+				if err != nil {
+					t.Errorf("unexpected error: %s", err)
+				}
+
+				return
+			}
+
+			var apiErr *ApiError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("expected an ApiError, got %v", err)
+			}
+
+			if apiErr.StatusCode != c.statusCode {
+				t.Errorf("expected status code %d, got %d", c.statusCode, apiErr.StatusCode)
+			}
+
+			var permanentErr *utils.PermanentError
+			if permanent := errors.As(err, &permanentErr); permanent != c.permanent {
+				t.Errorf("expected permanent to be %t, got %t", c.permanent, permanent)
+			}
+		})
+	}
+}
+
+// fakeListVmisApi answers ListVmis with a single page of vmis.
+type fakeListVmisApi struct {
+	Api
+
+	vmis []client.VirtualMachineImage
+}
+
+func (a *fakeListVmisApi) ListVmisWithResponse(ctx context.Context, params *client.ListVmisParams, reqEditors ...client.RequestEditorFn) (*client.ListVmisResponse, error) {
+	hasMore := false
+
+	return &client.ListVmisResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK, Header: http.Header{}},
+		JSON200: &client.VirtualMachineImageCollection{
+			HasMore: &hasMore,
+			Data:    &a.vmis,
+		},
+	}, nil
+}
+
+func TestVmiRefreshFunc(t *testing.T) {
+	id := int32(1)
+	creating := client.VirtualMachineImageStatusCreating
+
+	cases := []struct {
+		name  string
+		vmis  []client.VirtualMachineImage
+		state string
+	}{
+		{
+			name:  "status",
+			vmis:  []client.VirtualMachineImage{{Id: &id, Status: &creating}},
+			state: string(client.VirtualMachineImageStatusCreating),
+		},
+		{
+			name:  "missing status",
+			vmis:  []client.VirtualMachineImage{{Id: &id}},
+			state: string(client.VirtualMachineImageStatusAvailable),
+		},
+		{
+			name:  "missing vmi",
+			state: utils.NotFoundState,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			refresh := vmiRefreshFunc(NewVmiService(&fakeListVmisApi{vmis: c.vmis}), id)
+
+			_, state, err := refresh(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if state != c.state {
+				t.Errorf("expected state %q, got %q", c.state, state)
+			}
+		})
+	}
+}
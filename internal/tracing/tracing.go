@@ -0,0 +1,134 @@
+// Package tracing configures optional OpenTelemetry tracing of the provider.
+//
+// Tracing is disabled unless it's enabled through the standard OTEL_*
+// environment variables:
+//
+//   - OTEL_TRACES_EXPORTER selects the exporter: `otlp` or `console`. When
+//     unset, `otlp` is used if an OTLP endpoint is configured.
+//   - OTEL_EXPORTER_OTLP_ENDPOINT, OTEL_EXPORTER_OTLP_TRACES_ENDPOINT,
+//     OTEL_EXPORTER_OTLP_HEADERS, ... configure the OTLP/HTTP exporter.
+//   - OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES describe the resource.
+//
+// The `console` exporter writes the spans as JSON to the file named by
+// CRUNCHLOOP_OTEL_TRACES_FILE, or to stderr. Stdout is reserved for the
+// plugin protocol.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies the spans created by the provider.
+const instrumentationName = "github.com/crunchloop/terraform-provider-crunchloop"
+
+// serviceName is the default service.name of the traces.
+const serviceName = "terraform-provider-crunchloop"
+
+// Setup installs the global tracer provider and propagator according to the
+// OTEL_* environment variables. The returned function flushes the pending
+// spans and must be called before the process exits, it's a no-op when
+// tracing is disabled or couldn't be configured.
+func Setup(ctx context.Context, version string) (func(context.Context) error, error) {
+	noop := func(context.Context) error { return nil }
+
+	exporter, err := newExporter(ctx)
+	if err != nil || exporter == nil {
+		return noop, err
+	}
+
+	// Attributes from the environment take precedence over the defaults.
+	res, err := resource.New(ctx,
+		resource.WithTelemetrySDK(),
+		resource.WithSchemaURL(semconv.SchemaURL),
+		resource.WithAttributes(
+			semconv.ServiceName(serviceName),
+			semconv.ServiceVersion(version),
+		),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return noop, fmt.Errorf("failed to create tracing resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return provider.Shutdown, nil
+}
+
+func newExporter(ctx context.Context) (sdktrace.SpanExporter, error) {
+	exporter := os.Getenv("OTEL_TRACES_EXPORTER")
+	if exporter == "" && (os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != "") {
+		exporter = "otlp"
+	}
+
+	switch exporter {
+	case "", "none":
+		return nil, nil
+	case "otlp":
+		return otlptracehttp.New(ctx)
+	case "console":
+		var writer io.Writer = os.Stderr
+		if path := os.Getenv("CRUNCHLOOP_OTEL_TRACES_FILE"); path != "" {
+			file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open traces file: %w", err)
+			}
+
+			writer = file
+		}
+
+		return stdouttrace.New(stdouttrace.WithWriter(writer))
+	}
+
+	return nil, fmt.Errorf("unsupported OTEL_TRACES_EXPORTER %q, must be otlp, console or none", exporter)
+}
+
+// Tracer returns the tracer of the provider, a no-op one when tracing is
+// disabled.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start starts a span named after the given Terraform type and operation,
+// e.g. `crunchloop_vm.Create`.
+func Start(ctx context.Context, typeName string, operation string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, fmt.Sprintf("%s.%s", typeName, operation), trace.WithAttributes(attributes...))
+}
+
+// End ends a span started by Start, marking it as failed when the operation
+// produced error diagnostics.
+func End(span trace.Span, diags *diag.Diagnostics) {
+	if diags.HasError() {
+		for _, d := range diags.Errors() {
+			span.RecordError(fmt.Errorf("%s: %s", d.Summary(), d.Detail()))
+		}
+
+		span.SetStatus(codes.Error, diags.Errors()[0].Summary())
+	}
+
+	span.End()
+}
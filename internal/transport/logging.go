@@ -0,0 +1,179 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// loggingSubsystem is the tflog subsystem API requests are logged to. Its
+// level follows the provider one (TF_LOG_PROVIDER_CRUNCHLOOP) unless
+// TF_LOG_PROVIDER_CRUNCHLOOP_HTTP is set.
+const loggingSubsystem = "http"
+
+// redactedValue replaces secrets in logged headers and bodies.
+const redactedValue = "***"
+
+// redactedHeaders are never logged in clear.
+var redactedHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+// redactedFields are the JSON body fields that are never logged in clear.
+var redactedFields = map[string]bool{
+	"api_token":    true,
+	"password":     true,
+	"ssh_key":      true,
+	"ssh_password": true,
+	"token":        true,
+	"user_data":    true,
+}
+
+// LoggingDoer is a client.HttpRequestDoer that logs every API request: the
+// method, url, status and latency at DEBUG, and the headers and bodies at
+// TRACE. Credentials, ssh keys and user data are redacted.
+type LoggingDoer struct {
+	doer client.HttpRequestDoer
+}
+
+// Ensure LoggingDoer satisfies the generated client interface.
+var _ client.HttpRequestDoer = &LoggingDoer{}
+
+func NewLoggingDoer(doer client.HttpRequestDoer) *LoggingDoer {
+	return &LoggingDoer{
+		doer: doer,
+	}
+}
+
+func (d *LoggingDoer) Do(req *http.Request) (*http.Response, error) {
+	ctx := tflog.NewSubsystem(req.Context(), loggingSubsystem,
+		tflog.WithLevelFromEnv("TF_LOG_PROVIDER_CRUNCHLOOP_HTTP"),
+	)
+
+	fields := map[string]interface{}{
+		"method": req.Method,
+		"url":    req.URL.Redacted(),
+	}
+
+	tflog.SubsystemTrace(ctx, loggingSubsystem, "Sending Crunchloop API request", mergeFields(fields, map[string]interface{}{
+		"headers": redactHeaders(req.Header),
+		"body":    requestBody(req),
+	}))
+
+	start := time.Now()
+	resp, err := d.doer.Do(req)
+	fields["latency"] = time.Since(start).String()
+
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.SubsystemDebug(ctx, loggingSubsystem, "Crunchloop API request failed", fields)
+		return resp, err
+	}
+
+	fields["status"] = resp.StatusCode
+	if requestId := resp.Header.Get("X-Request-Id"); requestId != "" {
+		fields["request_id"] = requestId
+	}
+
+	tflog.SubsystemDebug(ctx, loggingSubsystem, "Received Crunchloop API response", fields)
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return resp, err
+	}
+
+	tflog.SubsystemTrace(ctx, loggingSubsystem, "Crunchloop API response body", mergeFields(fields, map[string]interface{}{
+		"headers": redactHeaders(resp.Header),
+		"body":    redactBody(body),
+	}))
+
+	return resp, nil
+}
+
+// requestBody returns the redacted request body without consuming it.
+func requestBody(req *http.Request) string {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody == nil {
+		return ""
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer body.Close()
+
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return ""
+	}
+
+	return redactBody(content)
+}
+
+func redactHeaders(headers http.Header) map[string]string {
+	result := make(map[string]string, len(headers))
+	for key := range headers {
+		result[key] = headers.Get(key)
+	}
+
+	for _, key := range redactedHeaders {
+		if _, ok := result[key]; ok {
+			result[key] = redactedValue
+		}
+	}
+
+	return result
+}
+
+// redactBody replaces the value of every sensitive field of a JSON body.
+// Bodies that aren't JSON are logged as is, the API only produces JSON.
+func redactBody(body []byte) string {
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return string(body)
+	}
+
+	redacted, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return string(body)
+	}
+
+	return string(redacted)
+}
+
+func redactValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, field := range value {
+			if redactedFields[strings.ToLower(key)] {
+				value[key] = redactedValue
+				continue
+			}
+
+			value[key] = redactValue(field)
+		}
+	case []interface{}:
+		for i := range value {
+			value[i] = redactValue(value[i])
+		}
+	}
+
+	return value
+}
+
+func mergeFields(fields ...map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	for _, f := range fields {
+		for key, value := range f {
+			result[key] = value
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,198 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+// doerFunc adapts a function to client.HttpRequestDoer.
+type doerFunc func(req *http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestRedactBody(t *testing.T) {
+	tests := map[string]struct {
+		body     string
+		expected string
+	}{
+		"ssh_password": {
+			body:     `{"name":"pve-1","ssh_password":"secret"}`,
+			expected: `{"name":"pve-1","ssh_password":"***"}`,
+		},
+		"password": {
+			body:     `{"password":"secret"}`,
+			expected: `{"password":"***"}`,
+		},
+		"ssh_key": {
+			body:     `{"ssh_key":"ssh-ed25519 AAAA"}`,
+			expected: `{"ssh_key":"***"}`,
+		},
+		"user_data": {
+			body:     `{"cores":1,"user_data":"#cloud-config"}`,
+			expected: `{"cores":1,"user_data":"***"}`,
+		},
+		"api_token": {
+			body:     `{"api_token":"secret"}`,
+			expected: `{"api_token":"***"}`,
+		},
+		"case insensitive": {
+			body:     `{"Password":"secret"}`,
+			expected: `{"Password":"***"}`,
+		},
+		"non string values": {
+			body:     `{"password":{"value":"secret"},"token":42}`,
+			expected: `{"password":"***","token":"***"}`,
+		},
+		"nested object": {
+			body:     `{"vm":{"name":"vm-1","user_data":"#cloud-config"}}`,
+			expected: `{"vm":{"name":"vm-1","user_data":"***"}}`,
+		},
+		"array": {
+			body:     `{"data":[{"id":1,"ssh_password":"a"},{"id":2,"ssh_password":"b"}]}`,
+			expected: `{"data":[{"id":1,"ssh_password":"***"},{"id":2,"ssh_password":"***"}]}`,
+		},
+		"top level array": {
+			body:     `[{"ssh_key":"a"},"password"]`,
+			expected: `[{"ssh_key":"***"},"password"]`,
+		},
+		"no secrets": {
+			body:     `{"id":1,"name":"vm-1"}`,
+			expected: `{"id":1,"name":"vm-1"}`,
+		},
+		"not json": {
+			body:     `<html>password=secret</html>`,
+			expected: `<html>password=secret</html>`,
+		},
+		"empty": {
+			body:     ``,
+			expected: ``,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := redactBody([]byte(test.body)); got != test.expected {
+				t.Errorf("expected %s, got %s", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestRedactHeaders(t *testing.T) {
+	tests := map[string]struct {
+		headers  http.Header
+		expected map[string]string
+	}{
+		"authorization": {
+			headers: http.Header{
+				"Authorization": []string{"Bearer secret"},
+				"Content-Type":  []string{"application/json"},
+			},
+			expected: map[string]string{
+				"Authorization": "***",
+				"Content-Type":  "application/json",
+			},
+		},
+		"cookies": {
+			headers: http.Header{
+				"Cookie":     []string{"session=secret"},
+				"Set-Cookie": []string{"session=secret"},
+			},
+			expected: map[string]string{
+				"Cookie":     "***",
+				"Set-Cookie": "***",
+			},
+		},
+		"no secrets": {
+			headers: http.Header{
+				"X-Request-Id": []string{"1"},
+			},
+			expected: map[string]string{
+				"X-Request-Id": "1",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := redactHeaders(test.headers)
+			if len(got) != len(test.expected) {
+				t.Fatalf("expected %v, got %v", test.expected, got)
+			}
+
+			for key, value := range test.expected {
+				if got[key] != value {
+					t.Errorf("expected %s header to be %q, got %q", key, value, got[key])
+				}
+			}
+		})
+	}
+}
+
+func TestLoggingDoer(t *testing.T) {
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	requestBody := `{"name":"vm-1","user_data":"#cloud-config"}`
+	responseBody := `{"id":1,"name":"vm-1","user_data":"#cloud-config"}`
+
+	doer := NewLoggingDoer(doerFunc(func(req *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if string(body) != requestBody {
+			t.Errorf("expected the request body to be sent as is, got %s", body)
+		}
+
+		return &http.Response{
+			StatusCode: 201,
+			Header:     http.Header{"X-Request-Id": []string{"42"}},
+			Body:       io.NopCloser(strings.NewReader(responseBody)),
+		}, nil
+	}))
+
+	req, err := http.NewRequestWithContext(ctx, "POST", "http://localhost/api/v1/vms", strings.NewReader(requestBody))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	req.Header.Set("Authorization", "Bearer secret")
+
+	resp, err := doer.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if string(body) != responseBody {
+		t.Errorf("expected the response body to be readable after logging, got %s", body)
+	}
+
+	logs := output.String()
+	for _, secret := range []string{"Bearer secret", "#cloud-config"} {
+		if strings.Contains(logs, secret) {
+			t.Errorf("expected %q to be redacted from the logs:\n%s", secret, logs)
+		}
+	}
+
+	if !strings.Contains(logs, `\"user_data\":\"***\"`) {
+		t.Errorf("expected the redacted bodies to be logged:\n%s", logs)
+	}
+
+	if !strings.Contains(logs, `"request_id":"42"`) {
+		t.Errorf("expected the request id to be logged:\n%s", logs)
+	}
+}
@@ -0,0 +1,183 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	defaultMinBackoff = 1 * time.Second
+	defaultMaxBackoff = 30 * time.Second
+)
+
+// RetryDoer is a client.HttpRequestDoer that retries requests failing with
+// transient errors: network errors, 429 and 5xx responses.
+//
+// Non-idempotent requests (POST) are only retried when the API guarantees
+// they weren't processed, that is on 429 responses or when the connection
+// couldn't be established, so a vm is never created twice.
+type RetryDoer struct {
+	doer       client.HttpRequestDoer
+	maxRetries int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+// Ensure RetryDoer satisfies the generated client interface.
+var _ client.HttpRequestDoer = &RetryDoer{}
+
+func NewRetryDoer(doer client.HttpRequestDoer, maxRetries int) *RetryDoer {
+	return &RetryDoer{
+		doer:       doer,
+		maxRetries: maxRetries,
+		minBackoff: defaultMinBackoff,
+		maxBackoff: defaultMaxBackoff,
+	}
+}
+
+func (d *RetryDoer) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.Body != nil && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		resp, err := d.doer.Do(req)
+
+		if attempt >= d.maxRetries || !d.shouldRetry(req, resp, err) {
+			return resp, err
+		}
+
+		// Requests with a body that can't be replayed can't be retried.
+		if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+			return resp, err
+		}
+
+		wait := d.backoff(attempt, resp)
+
+		fields := map[string]interface{}{
+			"method":  req.Method,
+			"url":     req.URL.Redacted(),
+			"attempt": attempt + 1,
+			"wait":    wait.String(),
+		}
+		if err != nil {
+			fields["error"] = err.Error()
+		} else {
+			fields["status"] = resp.StatusCode
+		}
+		tflog.Debug(ctx, "Retrying Crunchloop API request", fields)
+
+		if resp != nil {
+			// Drain the body so the connection can be reused.
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (d *RetryDoer) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+
+		if isIdempotent(req.Method) {
+			return true
+		}
+
+		// The request never reached the API if the connection couldn't be
+		// established.
+		var opErr *net.OpError
+		return errors.As(err, &opErr) && opErr.Op == "dial"
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	if resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented {
+		return isIdempotent(req.Method)
+	}
+
+	return false
+}
+
+// backoff returns how long to wait before the next attempt, honoring the
+// Retry-After header when present.
+func (d *RetryDoer) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+			if wait > d.maxBackoff {
+				return d.maxBackoff
+			}
+			return wait
+		}
+	}
+
+	wait := d.minBackoff << attempt
+	if wait > d.maxBackoff || wait <= 0 {
+		wait = d.maxBackoff
+	}
+
+	// Add jitter so parallel resources don't retry in lockstep.
+	half := wait / 2
+	if half <= 0 {
+		return wait
+	}
+
+	return half + time.Duration(rand.Int63n(int64(half)))
+}
+
+// retryAfter parses a Retry-After header, either in seconds or as an HTTP
+// date.
+func retryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+
+	return 0, false
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
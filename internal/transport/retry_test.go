@@ -0,0 +1,310 @@
+package transport
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testRetryDoer returns a RetryDoer with short backoffs, answering every
+// attempt with the next of the given responses. Attempts past the last
+// response succeed.
+func testRetryDoer(maxRetries int, attempts *[]string, responses ...func() (*http.Response, error)) *RetryDoer {
+	doer := NewRetryDoer(doerFunc(func(req *http.Request) (*http.Response, error) {
+		body := ""
+		if req.Body != nil {
+			content, _ := io.ReadAll(req.Body)
+			body = string(content)
+		}
+
+		index := len(*attempts)
+		*attempts = append(*attempts, body)
+		if index < len(responses) {
+			return responses[index]()
+		}
+
+		return testResponse(200, nil)()
+	}), maxRetries)
+	doer.minBackoff = time.Millisecond
+	doer.maxBackoff = 2 * time.Millisecond
+
+	return doer
+}
+
+func testResponse(status int, header http.Header) func() (*http.Response, error) {
+	return func() (*http.Response, error) {
+		if header == nil {
+			header = http.Header{}
+		}
+
+		return &http.Response{
+			StatusCode: status,
+			Header:     header,
+			Body:       io.NopCloser(strings.NewReader("")),
+		}, nil
+	}
+}
+
+func testError(err error) func() (*http.Response, error) {
+	return func() (*http.Response, error) {
+		return nil, err
+	}
+}
+
+var (
+	dialError = &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	readError = &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}
+)
+
+func TestRetryDoer(t *testing.T) {
+	tests := map[string]struct {
+		method     string
+		maxRetries int
+		response   func() (*http.Response, error)
+		attempts   int
+	}{
+		"POST 5xx": {
+			method:     http.MethodPost,
+			maxRetries: 3,
+			response:   testResponse(503, nil),
+			attempts:   1,
+		},
+		"POST network error after connect": {
+			method:     http.MethodPost,
+			maxRetries: 3,
+			response:   testError(readError),
+			attempts:   1,
+		},
+		"POST unexpected EOF": {
+			method:     http.MethodPost,
+			maxRetries: 3,
+			response:   testError(io.ErrUnexpectedEOF),
+			attempts:   1,
+		},
+		"POST 429": {
+			method:     http.MethodPost,
+			maxRetries: 3,
+			response:   testResponse(429, nil),
+			attempts:   2,
+		},
+		"POST dial failure": {
+			method:     http.MethodPost,
+			maxRetries: 3,
+			response:   testError(dialError),
+			attempts:   2,
+		},
+		"GET 5xx": {
+			method:     http.MethodGet,
+			maxRetries: 3,
+			response:   testResponse(500, nil),
+			attempts:   2,
+		},
+		"PUT 5xx": {
+			method:     http.MethodPut,
+			maxRetries: 3,
+			response:   testResponse(502, nil),
+			attempts:   2,
+		},
+		"DELETE 5xx": {
+			method:     http.MethodDelete,
+			maxRetries: 3,
+			response:   testResponse(504, nil),
+			attempts:   2,
+		},
+		"GET network error": {
+			method:     http.MethodGet,
+			maxRetries: 3,
+			response:   testError(readError),
+			attempts:   2,
+		},
+		"GET 501": {
+			method:     http.MethodGet,
+			maxRetries: 3,
+			response:   testResponse(501, nil),
+			attempts:   1,
+		},
+		"GET 4xx": {
+			method:     http.MethodGet,
+			maxRetries: 3,
+			response:   testResponse(404, nil),
+			attempts:   1,
+		},
+		"retries disabled": {
+			method:     http.MethodGet,
+			maxRetries: 0,
+			response:   testResponse(503, nil),
+			attempts:   1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var attempts []string
+			doer := testRetryDoer(test.maxRetries, &attempts, test.response)
+
+			req, err := http.NewRequest(test.method, "http://localhost/api/v1/vms", nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			_, _ = doer.Do(req)
+
+			if len(attempts) != test.attempts {
+				t.Errorf("expected %d attempts, got %d", test.attempts, len(attempts))
+			}
+		})
+	}
+}
+
+func TestRetryDoer_maxRetries(t *testing.T) {
+	var attempts []string
+	failure := testResponse(503, nil)
+	doer := testRetryDoer(2, &attempts, failure, failure, failure, failure)
+
+	req, err := http.NewRequest(http.MethodGet, "http://localhost/api/v1/vms", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	resp, err := doer.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if resp.StatusCode != 503 {
+		t.Errorf("expected the last response to be returned, got %d", resp.StatusCode)
+	}
+
+	if len(attempts) != 3 {
+		t.Errorf("expected 3 attempts, got %d", len(attempts))
+	}
+}
+
+func TestRetryDoer_replaysBody(t *testing.T) {
+	var attempts []string
+	doer := testRetryDoer(3, &attempts, testResponse(429, nil), testError(dialError))
+
+	body := `{"name":"vm-1"}`
+	req, err := http.NewRequest(http.MethodPost, "http://localhost/api/v1/vms", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	resp, err := doer.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if resp.StatusCode != 200 {
+		t.Errorf("expected the request to succeed, got %d", resp.StatusCode)
+	}
+
+	if len(attempts) != 3 {
+		t.Fatalf("expected 3 attempts, got %d", len(attempts))
+	}
+
+	for i, attempt := range attempts {
+		if attempt != body {
+			t.Errorf("expected attempt %d to send %s, got %q", i+1, body, attempt)
+		}
+	}
+}
+
+func TestRetryDoer_bodyWithoutGetBody(t *testing.T) {
+	var attempts []string
+	doer := testRetryDoer(3, &attempts, testResponse(429, nil))
+
+	req, err := http.NewRequest(http.MethodPost, "http://localhost/api/v1/vms", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	req.GetBody = nil
+
+	_, _ = doer.Do(req)
+
+	if len(attempts) != 1 {
+		t.Errorf("expected a body that can't be replayed not to be retried, got %d attempts", len(attempts))
+	}
+}
+
+func TestRetryDoer_backoff(t *testing.T) {
+	doer := &RetryDoer{
+		minBackoff: time.Second,
+		maxBackoff: 30 * time.Second,
+	}
+
+	retryAfter := func(value string) *http.Response {
+		return &http.Response{Header: http.Header{"Retry-After": []string{value}}}
+	}
+
+	tests := map[string]struct {
+		attempt int
+		resp    *http.Response
+		min     time.Duration
+		max     time.Duration
+	}{
+		"retry after seconds": {
+			resp: retryAfter("7"),
+			min:  7 * time.Second,
+			max:  7 * time.Second,
+		},
+		"retry after date": {
+			resp: retryAfter(time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat)),
+			min:  8 * time.Second,
+			max:  10 * time.Second,
+		},
+		"retry after date in the past": {
+			resp: retryAfter(time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat)),
+			min:  0,
+			max:  0,
+		},
+		"retry after seconds capped": {
+			resp: retryAfter("3600"),
+			min:  30 * time.Second,
+			max:  30 * time.Second,
+		},
+		"retry after date capped": {
+			resp: retryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)),
+			min:  30 * time.Second,
+			max:  30 * time.Second,
+		},
+		"invalid retry after": {
+			resp: retryAfter("soon"),
+			min:  500 * time.Millisecond,
+			max:  time.Second,
+		},
+		"first attempt": {
+			attempt: 0,
+			min:     500 * time.Millisecond,
+			max:     time.Second,
+		},
+		"exponential": {
+			attempt: 3,
+			min:     4 * time.Second,
+			max:     8 * time.Second,
+		},
+		"capped": {
+			attempt: 10,
+			min:     15 * time.Second,
+			max:     30 * time.Second,
+		},
+		"overflow": {
+			attempt: 100,
+			min:     15 * time.Second,
+			max:     30 * time.Second,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			wait := doer.backoff(test.attempt, test.resp)
+			if wait < test.min || wait > test.max {
+				t.Errorf("expected a wait between %s and %s, got %s", test.min, test.max, wait)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package transport
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracingDoer is a client.HttpRequestDoer that records a span for every API
+// request and propagates the trace context to the API in the request headers.
+// Wrapped by a RetryDoer, every attempt is recorded as its own span.
+type TracingDoer struct {
+	doer client.HttpRequestDoer
+}
+
+// Ensure TracingDoer satisfies the generated client interface.
+var _ client.HttpRequestDoer = &TracingDoer{}
+
+func NewTracingDoer(doer client.HttpRequestDoer) *TracingDoer {
+	return &TracingDoer{
+		doer: doer,
+	}
+}
+
+func (d *TracingDoer) Do(req *http.Request) (*http.Response, error) {
+	ctx, span := tracing.Tracer().Start(req.Context(), fmt.Sprintf("HTTP %s", req.Method),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("url.full", req.URL.Redacted()),
+			attribute.String("server.address", req.URL.Hostname()),
+		),
+	)
+	defer span.End()
+
+	// Clone the request so the caller's headers aren't modified, retries
+	// reuse them.
+	req = req.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := d.doer.Do(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return resp, err
+	}
+
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if requestId := resp.Header.Get("X-Request-Id"); requestId != "" {
+		span.SetAttributes(attribute.String("crunchloop.request_id", requestId))
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+
+	return resp, nil
+}
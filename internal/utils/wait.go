@@ -8,49 +8,153 @@ import (
 	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
 )
 
-func WaitForVmStatus(ctx context.Context, client *client.ClientWithResponses, id int32, status client.VirtualMachineStatus) error {
-	timeout := time.After(5 * time.Minute)    // 5 minutes timeout
-	ticker := time.NewTicker(5 * time.Second) // Check every 10 seconds
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-timeout:
-			return fmt.Errorf("timeout waiting for VM status to become '%s'", status)
-		case <-ticker.C:
-			vm, err := client.GetVmWithResponse(ctx, id)
+// NotFoundState is reported by refresh functions when the API reports the
+// object being waited on as not found.
+const NotFoundState = "not_found"
+
+// maxConsecutiveErrors is the number of transient API errors tolerated while
+// polling.
+const maxConsecutiveErrors = 3
+
+// States reported while waiting for a vm ip address.
+const (
+	ipAddressPendingState  = "pending"
+	ipAddressAssignedState = "assigned"
+)
+
+// rebootPendingState is reported while waiting for a vm reboot, until the vm
+// is observed leaving the `running` status or the reboot grace period ends.
+const rebootPendingState = "reboot_pending"
+
+// rebootGracePeriod is how long a vm that keeps reporting `running` after a
+// reboot was requested may take to report another status, a variable so tests
+// can shorten it.
+var rebootGracePeriod = 10 * time.Second
+
+// WaitForVmStatus polls the vm through refresh until it reaches the given
+// status. The wait is bounded by the context deadline, callers are expected
+// to derive it from the resource timeouts.
+func WaitForVmStatus(ctx context.Context, refresh StateRefreshFunc, id int32, status client.VirtualMachineStatus) error {
+	failure := []string{NotFoundState}
+	if status != "deleting" {
+		failure = append(failure, "deleting")
+	}
+
+	waiter := &StateWaiter{
+		Description:          fmt.Sprintf("vm %d", id),
+		Target:               []string{string(status)},
+		Failure:              failure,
+		Refresh:              refresh,
+		Delay:                defaultMinPollInterval,
+		MaxConsecutiveErrors: maxConsecutiveErrors,
+	}
+
+	_, err := waiter.Wait(ctx)
+
+	return err
+}
+
+// WaitForVmDeletion polls the vm through refresh until it reports
+// NotFoundState. The wait is bounded by the context deadline.
+func WaitForVmDeletion(ctx context.Context, refresh StateRefreshFunc, id int32) error {
+	waiter := &StateWaiter{
+		Description:          fmt.Sprintf("vm %d", id),
+		Target:               []string{NotFoundState},
+		Refresh:              refresh,
+		Delay:                defaultMinPollInterval,
+		MaxConsecutiveErrors: maxConsecutiveErrors,
+	}
+
+	_, err := waiter.Wait(ctx)
+
+	return err
+}
+
+// WaitForVmReboot polls the vm through refresh until it's running again after
+// a reboot was requested. The API has no rebooting status and Proxmox usually
+// reports the vm as running for the whole reboot, so `running` is accepted
+// once the vm was observed in another status, e.g. `updating`, or once the
+// reboot grace period passed without it reporting one. rebooting reports
+// whether the vm already left the running status, e.g. in the reboot
+// response. The wait is bounded by the context deadline.
+func WaitForVmReboot(ctx context.Context, refresh StateRefreshFunc, id int32, rebooting bool) error {
+	requestedAt := time.Now()
+
+	waiter := &StateWaiter{
+		Description: fmt.Sprintf("vm %d reboot", id),
+		Target:      []string{"running"},
+		Failure:     []string{NotFoundState, "deleting"},
+		Refresh: func(ctx context.Context) (interface{}, string, error) {
+			result, state, err := refresh(ctx)
 			if err != nil {
-				return err
+				return result, state, err
 			}
-			if *vm.JSON200.Status == status {
-				return nil
+
+			if state != "running" {
+				rebooting = true
+			} else if !rebooting && time.Since(requestedAt) < rebootGracePeriod {
+				return result, rebootPendingState, nil
 			}
-		}
+
+			return result, state, nil
+		},
+		Delay:                defaultMinPollInterval,
+		MaxConsecutiveErrors: maxConsecutiveErrors,
 	}
+
+	_, err := waiter.Wait(ctx)
+
+	return err
 }
 
-func WaitForVmDeletion(ctx context.Context, client *client.ClientWithResponses, id int32) error {
-	timeout := time.After(5 * time.Minute)    // 5 minutes timeout
-	ticker := time.NewTicker(5 * time.Second) // Check every 10 seconds
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-timeout:
-			return fmt.Errorf("timeout waiting for VM status to get deleted")
-		case <-ticker.C:
-			vmResponse, err := client.GetVmWithResponse(ctx, id)
-			if err != nil {
-				return err
+// WaitForVmIpAddress polls the vm until an IP address is assigned to its
+// network interface, it returns the vm as last observed. The wait is bounded
+// by the context deadline.
+func WaitForVmIpAddress(ctx context.Context, refresh StateRefreshFunc, id int32) (*client.VirtualMachine, error) {
+	waiter := &StateWaiter{
+		Description: fmt.Sprintf("vm %d ip address", id),
+		Pending:     []string{ipAddressPendingState},
+		Target:      []string{ipAddressAssignedState},
+		Failure:     []string{NotFoundState, "deleting"},
+		Refresh: func(ctx context.Context) (interface{}, string, error) {
+			result, state, err := refresh(ctx)
+			if err != nil || result == nil || state == "deleting" {
+				return result, state, err
 			}
 
-			if vmResponse.StatusCode() == 404 {
-				return nil
+			vm := result.(*client.VirtualMachine)
+			if vm.Nic == nil || vm.Nic.IpAddress == nil || *vm.Nic.IpAddress == "" {
+				return vm, ipAddressPendingState, nil
 			}
-		}
+
+			return vm, ipAddressAssignedState, nil
+		},
+		MaxConsecutiveErrors: maxConsecutiveErrors,
 	}
+
+	result, err := waiter.Wait(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.(*client.VirtualMachine), nil
+}
+
+// WaitForVmiStatus polls the vmi until it reaches the given status. The wait
+// is bounded by the context deadline, images are downloaded by the host so
+// callers should allow for it.
+func WaitForVmiStatus(ctx context.Context, refresh StateRefreshFunc, id int32, status client.VirtualMachineImageStatus) error {
+	waiter := &StateWaiter{
+		Description:          fmt.Sprintf("vmi %d", id),
+		Target:               []string{string(status)},
+		Failure:              []string{NotFoundState, "failed", "deleting"},
+		Refresh:              refresh,
+		Delay:                defaultMinPollInterval,
+		MaxPollInterval:      30 * time.Second,
+		MaxConsecutiveErrors: maxConsecutiveErrors,
+	}
+
+	_, err := waiter.Wait(ctx)
+
+	return err
 }
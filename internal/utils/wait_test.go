@@ -0,0 +1,125 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/client"
+)
+
+// fakeVm reports the given statuses in order, repeating the last one.
+type fakeVm struct {
+	statuses []client.VirtualMachineStatus
+	polls    int
+}
+
+func (v *fakeVm) refresh(ctx context.Context) (interface{}, string, error) {
+	status := v.statuses[len(v.statuses)-1]
+	if v.polls < len(v.statuses) {
+		status = v.statuses[v.polls]
+	}
+	v.polls++
+
+	id := int32(1)
+
+	return &client.VirtualMachine{Id: &id, Status: &status}, string(status), nil
+}
+
+func shortenPollIntervals(t *testing.T) {
+	t.Helper()
+
+	minInterval, maxInterval := defaultMinPollInterval, defaultMaxPollInterval
+	defaultMinPollInterval, defaultMaxPollInterval = time.Millisecond, 2*time.Millisecond
+	t.Cleanup(func() {
+		defaultMinPollInterval, defaultMaxPollInterval = minInterval, maxInterval
+	})
+}
+
+func TestWaitForVmReboot(t *testing.T) {
+	shortenPollIntervals(t)
+
+	vm := &fakeVm{
+		statuses: []client.VirtualMachineStatus{
+			client.VirtualMachineStatusRunning,
+			client.VirtualMachineStatusRunning,
+			client.VirtualMachineStatusUpdating,
+			client.VirtualMachineStatusRunning,
+		},
+	}
+
+	if err := WaitForVmReboot(context.Background(), vm.refresh, 1, false); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if vm.polls != 4 {
+		t.Errorf("expected the wait to end once the vm is running again after 4 polls, got %d", vm.polls)
+	}
+}
+
+func TestWaitForVmReboot_rebooting(t *testing.T) {
+	shortenPollIntervals(t)
+
+	vm := &fakeVm{
+		statuses: []client.VirtualMachineStatus{client.VirtualMachineStatusRunning},
+	}
+
+	if err := WaitForVmReboot(context.Background(), vm.refresh, 1, true); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if vm.polls != 1 {
+		t.Errorf("expected the wait to end on the first poll, got %d polls", vm.polls)
+	}
+}
+
+func TestWaitForVmReboot_alwaysRunning(t *testing.T) {
+	shortenPollIntervals(t)
+
+	gracePeriod := rebootGracePeriod
+	rebootGracePeriod = 20 * time.Millisecond
+	t.Cleanup(func() {
+		rebootGracePeriod = gracePeriod
+	})
+
+	vm := &fakeVm{
+		statuses: []client.VirtualMachineStatus{client.VirtualMachineStatusRunning},
+	}
+
+	start := time.Now()
+
+	if err := WaitForVmReboot(context.Background(), vm.refresh, 1, false); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if elapsed := time.Since(start); elapsed < rebootGracePeriod {
+		t.Errorf("expected the wait to last the %s grace period, it ended after %s", rebootGracePeriod, elapsed)
+	}
+
+	if vm.polls < 2 {
+		t.Errorf("expected the vm to be polled during the grace period, got %d polls", vm.polls)
+	}
+}
+
+func TestWaitForVmReboot_withinGracePeriod(t *testing.T) {
+	shortenPollIntervals(t)
+
+	vm := &fakeVm{
+		statuses: []client.VirtualMachineStatus{client.VirtualMachineStatusRunning},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := WaitForVmReboot(ctx, vm.refresh, 1, false)
+
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("expected a timeout error, got %v", err)
+	}
+
+	if timeoutErr.LastState != rebootPendingState {
+		t.Errorf("expected last state %q, got %q", rebootPendingState, timeoutErr.LastState)
+	}
+}
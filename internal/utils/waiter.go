@@ -0,0 +1,238 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Default polling intervals, variables so tests can shorten them.
+var (
+	defaultMinPollInterval = 2 * time.Second
+	defaultMaxPollInterval = 15 * time.Second
+)
+
+// StateRefreshFunc fetches the object being waited on and returns it along
+// with its current state.
+type StateRefreshFunc func(ctx context.Context) (result interface{}, state string, err error)
+
+// StateWaiter polls an object until it reaches one of the Target states.
+//
+// Polling backs off exponentially (with jitter) from MinPollInterval up to
+// MaxPollInterval. The wait is bounded by the context deadline, callers derive
+// it from the resource timeouts.
+type StateWaiter struct {
+	// Description of the object being waited on, used in error messages,
+	// e.g. "vm 12".
+	Description string
+
+	// Pending states are expected while waiting. When empty, any state that
+	// is neither a Target nor a Failure state is considered pending.
+	Pending []string
+
+	// Target states end the wait successfully.
+	Target []string
+
+	// Failure states abort the wait, e.g. "deleting" when waiting for a vm
+	// to become "running".
+	Failure []string
+
+	Refresh StateRefreshFunc
+
+	Delay           time.Duration
+	MinPollInterval time.Duration
+	MaxPollInterval time.Duration
+
+	// MaxConsecutiveErrors is the number of consecutive Refresh errors that
+	// are tolerated before giving up. Zero means the first error aborts.
+	MaxConsecutiveErrors int
+}
+
+// TimeoutError is returned when the object didn't reach a Target state in
+// time.
+type TimeoutError struct {
+	Description string
+	Target      []string
+	LastState   string
+	LastError   error
+}
+
+func (e *TimeoutError) Error() string {
+	lastState := e.LastState
+	if lastState == "" {
+		lastState = "unknown"
+	}
+
+	message := fmt.Sprintf("timeout waiting for %s to become '%s', last observed status: '%s'", e.Description, strings.Join(e.Target, "', '"), lastState)
+	if e.LastError != nil {
+		message += fmt.Sprintf(", last error: %s", e.LastError)
+	}
+
+	return message
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.LastError
+}
+
+// UnexpectedStateError is returned when the object reaches a Failure state or
+// a state that isn't Pending.
+type UnexpectedStateError struct {
+	Description string
+	Target      []string
+	State       string
+}
+
+func (e *UnexpectedStateError) Error() string {
+	return fmt.Sprintf("%s reached unexpected status '%s' while waiting for '%s'", e.Description, e.State, strings.Join(e.Target, "', '"))
+}
+
+// PermanentError wraps a Refresh error that aborts the wait right away instead
+// of being tolerated as transient, e.g. the API rejecting the credentials.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// Wait blocks until the object reaches a Target state and returns the last
+// result obtained from Refresh.
+func (w *StateWaiter) Wait(ctx context.Context) (result interface{}, err error) {
+	var polls int
+	var lastState string
+
+	ctx, span := tracing.Tracer().Start(ctx, fmt.Sprintf("wait %s", w.Description))
+	span.SetAttributes(attribute.StringSlice("crunchloop.wait.target", w.Target))
+	defer func() {
+		span.SetAttributes(
+			attribute.Int("crunchloop.wait.polls", polls),
+			attribute.String("crunchloop.wait.last_state", lastState),
+		)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	minInterval := w.MinPollInterval
+	if minInterval <= 0 {
+		minInterval = defaultMinPollInterval
+	}
+
+	maxInterval := w.MaxPollInterval
+	if maxInterval < minInterval {
+		maxInterval = defaultMaxPollInterval
+	}
+	if maxInterval < minInterval {
+		maxInterval = minInterval
+	}
+
+	var (
+		lastResult interface{}
+		lastErr    error
+		errorCount int
+	)
+
+	wait := w.Delay
+	interval := minInterval
+
+	for {
+		timer := time.NewTimer(wait)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return lastResult, &TimeoutError{
+					Description: w.Description,
+					Target:      w.Target,
+					LastState:   lastState,
+					LastError:   lastErr,
+				}
+			}
+
+			return lastResult, ctx.Err()
+		case <-timer.C:
+		}
+
+		polls++
+
+		result, state, err := w.Refresh(ctx)
+
+		var permanentErr *PermanentError
+		if errors.As(err, &permanentErr) {
+			return lastResult, permanentErr.Err
+		}
+
+		if err != nil {
+			// Errors caused by the context expiring are reported on the
+			// next iteration.
+			if ctx.Err() == nil {
+				errorCount++
+				lastErr = err
+
+				if errorCount > w.MaxConsecutiveErrors {
+					return lastResult, err
+				}
+			}
+		} else {
+			errorCount = 0
+			lastErr = nil
+			lastResult = result
+			lastState = state
+
+			if containsState(w.Target, state) {
+				return result, nil
+			}
+
+			if containsState(w.Failure, state) || (len(w.Pending) > 0 && !containsState(w.Pending, state)) {
+				return result, &UnexpectedStateError{
+					Description: w.Description,
+					Target:      w.Target,
+					State:       state,
+				}
+			}
+		}
+
+		wait = jitter(interval)
+		interval *= 2
+		if interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+}
+
+func containsState(states []string, state string) bool {
+	for _, s := range states {
+		if s == state {
+			return true
+		}
+	}
+
+	return false
+}
+
+// jitter returns a random duration in [d/2, d).
+func jitter(d time.Duration) time.Duration {
+	half := d / 2
+	if half <= 0 {
+		return d
+	}
+
+	return half + time.Duration(rand.Int63n(int64(half)))
+}
@@ -0,0 +1,231 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// refreshStep is the outcome of one Refresh call.
+type refreshStep struct {
+	state string
+	err   error
+}
+
+// testWaiter returns a StateWaiter polling every millisecond through the
+// given steps, repeating the last one. polls counts the Refresh calls.
+func testWaiter(polls *int, steps ...refreshStep) *StateWaiter {
+	return &StateWaiter{
+		Description: "vm 1",
+		Target:      []string{"running"},
+		Failure:     []string{"deleting"},
+		Refresh: func(ctx context.Context) (interface{}, string, error) {
+			step := steps[len(steps)-1]
+			if *polls < len(steps) {
+				step = steps[*polls]
+			}
+			*polls++
+
+			if step.err != nil {
+				return nil, "", step.err
+			}
+
+			return step.state, step.state, nil
+		},
+		MinPollInterval: time.Millisecond,
+		MaxPollInterval: 2 * time.Millisecond,
+	}
+}
+
+func TestStateWaiter_target(t *testing.T) {
+	var polls int
+	waiter := testWaiter(&polls, refreshStep{state: "creating"}, refreshStep{state: "creating"}, refreshStep{state: "running"})
+
+	result, err := waiter.Wait(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if result != "running" {
+		t.Errorf("expected the last result to be returned, got %v", result)
+	}
+
+	if polls != 3 {
+		t.Errorf("expected 3 polls, got %d", polls)
+	}
+}
+
+func TestStateWaiter_failure(t *testing.T) {
+	var polls int
+	waiter := testWaiter(&polls, refreshStep{state: "creating"}, refreshStep{state: "deleting"})
+
+	result, err := waiter.Wait(context.Background())
+
+	var stateErr *UnexpectedStateError
+	if !errors.As(err, &stateErr) {
+		t.Fatalf("expected an UnexpectedStateError, got %v", err)
+	}
+
+	if stateErr.State != "deleting" {
+		t.Errorf("expected the failure state to be reported, got %s", stateErr.State)
+	}
+
+	if result != "deleting" {
+		t.Errorf("expected the last result to be returned, got %v", result)
+	}
+}
+
+func TestStateWaiter_notPending(t *testing.T) {
+	var polls int
+	waiter := testWaiter(&polls, refreshStep{state: "creating"}, refreshStep{state: "stopped"})
+	waiter.Pending = []string{"creating"}
+
+	_, err := waiter.Wait(context.Background())
+
+	var stateErr *UnexpectedStateError
+	if !errors.As(err, &stateErr) {
+		t.Fatalf("expected an UnexpectedStateError, got %v", err)
+	}
+
+	if stateErr.State != "stopped" {
+		t.Errorf("expected the unexpected state to be reported, got %s", stateErr.State)
+	}
+}
+
+func TestStateWaiter_anyStatePending(t *testing.T) {
+	var polls int
+	waiter := testWaiter(&polls, refreshStep{state: "creating"}, refreshStep{state: "updating"}, refreshStep{state: "running"})
+
+	if _, err := waiter.Wait(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestStateWaiter_toleratesErrors(t *testing.T) {
+	refreshErr := errors.New("connection reset by peer")
+
+	var polls int
+	waiter := testWaiter(&polls,
+		refreshStep{err: refreshErr},
+		refreshStep{err: refreshErr},
+		refreshStep{state: "creating"},
+		refreshStep{err: refreshErr},
+		refreshStep{err: refreshErr},
+		refreshStep{state: "running"},
+	)
+	waiter.MaxConsecutiveErrors = 2
+
+	if _, err := waiter.Wait(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if polls != 6 {
+		t.Errorf("expected 6 polls, got %d", polls)
+	}
+}
+
+func TestStateWaiter_tooManyErrors(t *testing.T) {
+	refreshErr := errors.New("connection reset by peer")
+
+	var polls int
+	waiter := testWaiter(&polls, refreshStep{state: "creating"}, refreshStep{err: refreshErr})
+	waiter.MaxConsecutiveErrors = 2
+
+	result, err := waiter.Wait(context.Background())
+	if !errors.Is(err, refreshErr) {
+		t.Fatalf("expected the refresh error, got %v", err)
+	}
+
+	if result != "creating" {
+		t.Errorf("expected the last successful result to be returned, got %v", result)
+	}
+
+	if polls != 4 {
+		t.Errorf("expected 4 polls, got %d", polls)
+	}
+}
+
+func TestStateWaiter_permanentError(t *testing.T) {
+	refreshErr := errors.New("unauthorized")
+
+	var polls int
+	waiter := testWaiter(&polls, refreshStep{state: "creating"}, refreshStep{err: &PermanentError{Err: refreshErr}})
+	waiter.MaxConsecutiveErrors = 3
+
+	_, err := waiter.Wait(context.Background())
+	if err != refreshErr {
+		t.Fatalf("expected the unwrapped refresh error, got %v", err)
+	}
+
+	if polls != 2 {
+		t.Errorf("expected the wait to abort on the first permanent error after 2 polls, got %d", polls)
+	}
+}
+
+func TestStateWaiter_timeout(t *testing.T) {
+	var polls int
+	waiter := testWaiter(&polls, refreshStep{state: "creating"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	result, err := waiter.Wait(ctx)
+
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("expected a TimeoutError, got %v", err)
+	}
+
+	if timeoutErr.LastState != "creating" {
+		t.Errorf("expected the last state to be reported, got %q", timeoutErr.LastState)
+	}
+
+	if result != "creating" {
+		t.Errorf("expected the last result to be returned, got %v", result)
+	}
+}
+
+func TestStateWaiter_timeoutAfterErrors(t *testing.T) {
+	refreshErr := errors.New("connection reset by peer")
+
+	var polls int
+	waiter := testWaiter(&polls, refreshStep{state: "creating"}, refreshStep{err: refreshErr})
+	waiter.MaxConsecutiveErrors = 1000
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := waiter.Wait(ctx)
+
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("expected a TimeoutError, got %v", err)
+	}
+
+	if timeoutErr.LastState != "creating" {
+		t.Errorf("expected the last state to be reported, got %q", timeoutErr.LastState)
+	}
+
+	if !errors.Is(err, refreshErr) {
+		t.Errorf("expected the last error to be wrapped, got %v", err)
+	}
+}
+
+func TestStateWaiter_canceled(t *testing.T) {
+	var polls int
+	waiter := testWaiter(&polls, refreshStep{state: "creating"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	_, err := waiter.Wait(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the context to be canceled, got %v", err)
+	}
+
+	var timeoutErr *TimeoutError
+	if errors.As(err, &timeoutErr) {
+		t.Errorf("expected a cancellation not to be reported as a timeout")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"log"
 
 	"github.com/crunchloop/terraform-provider-crunchloop/internal/provider"
+	"github.com/crunchloop/terraform-provider-crunchloop/internal/tracing"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 )
 
@@ -39,7 +40,22 @@ func main() {
 		Debug:   debug,
 	}
 
-	err := providerserver.Serve(context.Background(), provider.New(version), opts)
+	ctx := context.Background()
+
+	// Tracing is optional, it's enabled through the OTEL_* environment
+	// variables. A misconfiguration disables it rather than the provider.
+	shutdown, err := tracing.Setup(ctx, version)
+	if err != nil {
+		log.Printf("tracing disabled: %s", err)
+	}
+
+	err = providerserver.Serve(ctx, provider.New(version), opts)
+
+	// Flush the pending spans before exiting, Terraform stops the provider
+	// once it's done with it.
+	if shutdownErr := shutdown(ctx); shutdownErr != nil {
+		log.Print(shutdownErr.Error())
+	}
 
 	if err != nil {
 		log.Fatal(err.Error())
@@ -0,0 +1,155 @@
+// Command genlimits generates Go constants for the minimum and maximum values
+// declared on the request body properties of the OpenAPI specification, so
+// plan-time validation stays in sync with the API.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func main() {
+	specPath := flag.String("spec", "openapi.yaml", "path to the OpenAPI specification")
+	output := flag.String("output", "limits.gen.go", "path of the generated file")
+	pkg := flag.String("package", "client", "package of the generated file")
+	flag.Parse()
+
+	loader := openapi3.NewLoader()
+	spec, err := loader.LoadFromFile(*specPath)
+	if err != nil {
+		log.Fatalf("failed to load %s: %s", *specPath, err)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by genlimits from %s. DO NOT EDIT.\n\n", *specPath)
+	fmt.Fprintf(&buf, "package %s\n\n", *pkg)
+
+	for _, operation := range operations(spec) {
+		properties := bodyProperties(operation)
+		if len(properties) == 0 {
+			continue
+		}
+
+		name := pascalCase(operation.OperationID)
+		fmt.Fprintf(&buf, "// Limits of the %s request body.\nconst (\n", operation.OperationID)
+		for _, property := range properties {
+			fmt.Fprintf(&buf, "%s", property.constants(name))
+		}
+		fmt.Fprintf(&buf, ")\n\n")
+	}
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("failed to format generated code: %s", err)
+	}
+
+	if err := os.WriteFile(*output, source, 0o644); err != nil {
+		log.Fatalf("failed to write %s: %s", *output, err)
+	}
+}
+
+type limitedProperty struct {
+	name   string
+	goType string
+	min    *float64
+	max    *float64
+}
+
+func (p limitedProperty) constants(prefix string) string {
+	var b strings.Builder
+
+	if p.min != nil {
+		fmt.Fprintf(&b, "\t%s%sMinimum %s = %v\n", prefix, pascalCase(p.name), p.goType, *p.min)
+	}
+
+	if p.max != nil {
+		fmt.Fprintf(&b, "\t%s%sMaximum %s = %v\n", prefix, pascalCase(p.name), p.goType, *p.max)
+	}
+
+	return b.String()
+}
+
+// operations returns the operations of the specification sorted by id so the
+// output is stable.
+func operations(spec *openapi3.T) []*openapi3.Operation {
+	var result []*openapi3.Operation
+
+	for _, item := range spec.Paths.Map() {
+		for _, operation := range item.Operations() {
+			if operation.OperationID != "" {
+				result = append(result, operation)
+			}
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].OperationID < result[j].OperationID
+	})
+
+	return result
+}
+
+func bodyProperties(operation *openapi3.Operation) []limitedProperty {
+	if operation.RequestBody == nil || operation.RequestBody.Value == nil {
+		return nil
+	}
+
+	media := operation.RequestBody.Value.Content.Get("application/json")
+	if media == nil || media.Schema == nil || media.Schema.Value == nil {
+		return nil
+	}
+
+	var result []limitedProperty
+	for name, ref := range media.Schema.Value.Properties {
+		schema := ref.Value
+		if schema == nil || (schema.Min == nil && schema.Max == nil) {
+			continue
+		}
+
+		result = append(result, limitedProperty{
+			name:   name,
+			goType: goType(schema),
+			min:    schema.Min,
+			max:    schema.Max,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].name < result[j].name
+	})
+
+	return result
+}
+
+func goType(schema *openapi3.Schema) string {
+	switch schema.Format {
+	case "int32":
+		return "int32"
+	case "int64":
+		return "int64"
+	}
+
+	if schema.Type.Is("integer") {
+		return "int"
+	}
+
+	return "float64"
+}
+
+func pascalCase(value string) string {
+	var b strings.Builder
+
+	for _, part := range strings.FieldsFunc(value, func(r rune) bool { return r == '_' || r == '-' }) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+
+	return b.String()
+}